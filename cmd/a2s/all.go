@@ -2,42 +2,133 @@ package main
 
 import (
 	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
 )
 
+// executeAll queries info, players and rules in one pipelined round trip,
+// the rules parser is forced by --game or chosen by the AppID from A2S_INFO unless --skip-info is set.
 func executeAll(cmd *AllCommand) {
-	if cmd.Args.Host == "" {
+	server, single := cmd.Args.server(cmd.BatchOptions)
+	if !single {
+		runBatch(cmd.Args.targets(cmd.BatchOptions), cmd.BatchOptions, cmd.ConnectionOptions, NewFormatter(cmd.Format, cmd.TemplateOptions),
+			table.Row{"Name", "Map", "Players", "Version", "Ping", "Rules"},
+			func(client *a2s.Client, target string) (any, []table.Row, error) {
+				opts := cmd.RulesOptions
+				opts.Game = config.game(target, opts.Game)
+
+				return queryAllSummary(client, opts)
+			})
+		return
+	}
+
+	if server.Host == "" {
 		fatal("Host must be provided")
 	}
 
-	client := createClient(cmd.Args.Host, cmd.Args.Port, cmd.Timeout, cmd.Buffer)
+	client := createClient(server, cmd.ConnectionOptions)
 	defer closeClient(client)
 
-	// Execute info
-	infoCmd := InfoCommand{
-		GlobalOptions: cmd.GlobalOptions,
+	opts := cmd.RulesOptions
+	opts.Game = config.game(server.Host, opts.Game)
+
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	address := client.Address.String()
+
+	all, err := queryAll(client, opts)
+	if all == nil || all.Info == nil {
+		fatalf("Failed to get server info: %s", err)
 	}
-	infoCmd.Args.Host = cmd.Args.Host
-	infoCmd.Args.Port = cmd.Args.Port
-	executeInfo(&infoCmd)
 
+	printInfo(all.Info, formatter, address)
 	fmt.Println()
 
-	// Execute rules
-	rulesCmd := RulesCommand{
-		GlobalOptions: cmd.GlobalOptions,
-		RulesOptions:  cmd.RulesOptions,
+	switch {
+	case all.Rules != nil:
+		printRulesA3SB(all.Rules, formatter, address)
+		fmt.Println()
+	case all.RawRules != nil && opts.Raw:
+		printRulesStandard(all.RawRules, formatter, address)
+		fmt.Println()
+	case all.RawRules != nil:
+		rules := make(map[string]string, len(all.RawRules))
+		for k, v := range a2s.ParseRuleValues(all.RawRules) {
+			rules[k] = fmt.Sprint(v)
+		}
+		printRulesStandard(rules, formatter, address)
+		fmt.Println()
 	}
-	rulesCmd.Args.Host = cmd.Args.Host
-	rulesCmd.Args.Port = cmd.Args.Port
-	executeRules(&rulesCmd)
 
-	fmt.Println()
+	printPlayers(all.Players, formatter, address)
+	fatalPartial(err)
+}
+
+// queryAll queries everything in one pipelined round trip. Rules are parsed with A3SB for the game
+// from options or, unless raw rules or skip-info are requested, for Arma 3 and DayZ detected by Info.ID.
+// Received parts are returned together with the error of failed ones.
+func queryAll(client *a2s.Client, opts RulesOptions) (*a3sb.All, error) {
+	if opts.Raw || (opts.SkipInfo && opts.Game == "") {
+		all, err := client.GetAll()
+		if all == nil {
+			return nil, err
+		}
+		return &a3sb.All{Info: all.Info, RawRules: all.Rules, Players: all.Players}, err
+	}
+
+	var game uint64
+	if opts.Game != "" {
+		var err error
+		if game, err = gameAppID(opts.Game); err != nil {
+			return nil, err
+		}
+	}
+
+	return (&a3sb.Client{Client: client}).GetAllGame(game)
+}
+
+// queryAllSummary queries everything in one pipelined round trip for batch mode and returns
+// info, rules and players and the info summary with rules count as table row.
+// Received parts are returned together with the error of failed ones.
+func queryAllSummary(client *a2s.Client, opts RulesOptions) (any, []table.Row, error) {
+	all, err := queryAll(client, opts)
+	if all == nil || all.Info == nil {
+		return nil, nil, err
+	}
+
+	var (
+		rules any
+		count int
+	)
+	switch {
+	case all.Rules != nil:
+		rules, count = all.Rules, a3sbRuleCount(all.Rules)
+	case all.RawRules != nil && opts.Raw:
+		rules, count = all.RawRules, len(all.RawRules)
+	case all.RawRules != nil:
+		rules, count = a2s.ParseRuleValues(all.RawRules), len(all.RawRules)
+	}
+
+	result := allView{Info: newInfoView(all.Info), Players: all.Players, Rules: rules}
+	return result, []table.Row{append(infoRow(all.Info), count)}, err
+}
+
+// a3sbRuleCount returns the number of list entries and extra rules in A3SB rules.
+func a3sbRuleCount(rules *a3sb.Rules) int {
+	return len(rules.DLC) + len(rules.CreatorDLC) + len(rules.Mods) + len(rules.Signatures) + len(rules.ExtraRules)
+}
+
+// allView is the result of the all command in batch mode, as printed in JSON and templates.
+type allView struct {
+	Rules   any          `json:"rules,omitempty"` // map[string]string, parsed rule values or *a3sb.Rules
+	Info    infoView     `json:"info"`
+	Players []a2s.Player `json:"players"`
+}
 
-	// Execute players
-	playersCmd := PlayersCommand{
-		GlobalOptions: cmd.GlobalOptions,
+// fatalPartial exits with error if some parts of a pipelined query failed after printing the received ones.
+func fatalPartial(err error) {
+	if err != nil {
+		fatalf("Some responses failed: %s", err)
 	}
-	playersCmd.Args.Host = cmd.Args.Host
-	playersCmd.Args.Port = cmd.Args.Port
-	executePlayers(&playersCmd)
 }
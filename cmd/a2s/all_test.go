@@ -0,0 +1,60 @@
+package main
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a2stest"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+)
+
+func TestQueryAll(t *testing.T) {
+	server, err := a2stest.NewServer(a2stest.Normal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }()
+
+	client, err := a2s.NewWithAddr(server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	client.Timeout = time.Second
+
+	for _, opts := range []RulesOptions{{}, {Raw: true}, {SkipInfo: true}} {
+		all, err := queryAll(client, opts)
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		}
+		if all.Rules != nil || !maps.Equal(all.RawRules, a2stest.Rules) {
+			t.Errorf("%+v: want standard rules, got %+v", opts, all)
+		}
+	}
+
+	all, _ := queryAll(client, RulesOptions{Game: "arma3", SkipInfo: true})
+	if all == nil || all.RawRules != nil {
+		t.Errorf("--game arma3: want A3SB parser, got %+v", all)
+	}
+
+	// Unknown game of a config server is reported as error of that server in batch mode
+	if all, err := queryAll(client, RulesOptions{Game: "quake"}); all != nil || err == nil {
+		t.Errorf("--game quake: want error, got %+v, %v", all, err)
+	}
+	if _, _, err := queryRules(client, RulesOptions{Game: "quake"}); err == nil {
+		t.Error("queryRules() --game quake: want error")
+	}
+}
+
+func TestA3SBRuleCount(t *testing.T) {
+	rules := &a3sb.Rules{
+		Mods:       make([]a3sb.Mod, 3),
+		Signatures: []string{"a3", "cba"},
+		ExtraRules: map[string]string{"key": "value"},
+	}
+	if got := a3sbRuleCount(rules); got != 6 {
+		t.Errorf("a3sbRuleCount = %d, want 6", got)
+	}
+}
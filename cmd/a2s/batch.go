@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/filter"
+)
+
+// TargetArgs defines positional arguments of commands accepting many servers.
+type TargetArgs struct {
+	Host string   `positional-arg-name:"host" description:"Server host (with optional port, e.g., 127.0.0.1:27016), @name or @group from the config"`
+	Port string   `positional-arg-name:"port" description:"Query port (if not included in host) or the next server"`
+	More []string `positional-arg-name:"server" description:"More servers in the format host:port"`
+}
+
+// BatchOptions defines options of commands querying many servers at once.
+type BatchOptions struct {
+	TargetsFile string `long:"targets" description:"File with servers, one host:port per line (- for stdin)"`
+	Parallel    int    `long:"parallel" default:"16" description:"Maximum number of servers queried at once"`
+	Filter      string `long:"filter" description:"Print only servers matching the filter expression, e.g. 'players >= 10 && !password'"`
+}
+
+// batchResult is a JSON Lines object of a single server in batch mode.
+type batchResult struct {
+	Address string `json:"address"`
+	Result  any    `json:"result,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// batchQuery queries the server of target and returns its JSON result and rows of the merged table.
+// Result and rows may be set together with an error for partial responses.
+type batchQuery func(client *a2s.Client, target string) (any, []table.Row, error)
+
+// server returns the single server of the arguments, false if servers are given
+// for batch mode: more than one, a @group, a --targets file, stdin or a --filter.
+func (a TargetArgs) server(batch BatchOptions) (ServerArgs, bool) {
+	if batch.TargetsFile != "" || batch.Filter != "" || len(a.More) > 0 || (a.Port != "" && !isPort(a.Port)) || config.isGroup(a.Host) {
+		return ServerArgs{}, false
+	}
+	if a.Host == "" && stdinPiped() {
+		return ServerArgs{}, false
+	}
+
+	return ServerArgs{Host: a.Host, Port: a.Port}, true
+}
+
+// targets returns addresses of servers from arguments and the --targets file or stdin,
+// @group targets are replaced with their servers.
+func (a TargetArgs) targets(batch BatchOptions) []string {
+	var targets []string
+	if a.Host != "" {
+		if isPort(a.Port) {
+			targets = append(targets, serverAddress(a.Host, a.Port))
+		} else {
+			targets = append(targets, a.Host)
+			if a.Port != "" {
+				targets = append(targets, a.Port)
+			}
+		}
+	}
+	targets = append(targets, a.More...)
+
+	switch {
+	case batch.TargetsFile == "-", batch.TargetsFile == "" && len(targets) == 0:
+		targets = append(targets, readTargets(os.Stdin)...)
+
+	case batch.TargetsFile != "":
+		file, err := os.Open(batch.TargetsFile)
+		if err != nil {
+			fatalf("Failed to read targets: %s", err)
+		}
+		targets = append(targets, readTargets(file)...)
+		_ = file.Close()
+	}
+
+	if len(targets) == 0 {
+		fatal("Host must be provided")
+	}
+
+	return config.expand(targets)
+}
+
+// readTargets reads servers one per line as host:port or "host port",
+// empty lines and lines starting with # are skipped.
+func readTargets(r io.Reader) []string {
+	var targets []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) > 1 && isPort(fields[1]) {
+			targets = append(targets, serverAddress(fields[0], fields[1]))
+		} else {
+			targets = append(targets, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fatalf("Failed to read targets: %s", err)
+	}
+
+	return targets
+}
+
+// runBatch queries all targets with at most --parallel queries at once.
+// JSON format prints a JSON Lines object per server as soon as it is queried and template format
+// executes the template on the same object. Other formats print a merged table with address
+// and error columns in the order of targets.
+// Servers not matching --filter are left out, failed servers are always printed.
+// Exits with code 1 if any server failed.
+func runBatch(targets []string, batch BatchOptions, conn ConnectionOptions, formatter *Formatter, header table.Row, query batchQuery) {
+	parallel := batch.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	match := compileFilter(batch.Filter)
+
+	type outcome struct {
+		result any
+		err    error
+		rows   []table.Row
+		skip   bool // Not matching the filter
+	}
+	outcomes := make([]outcome, len(targets))
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	slots := make(chan struct{}, parallel)
+
+	for i, target := range targets {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			var o outcome
+			address, serverConn, err := config.server(target, conn)
+			if err == nil {
+				address, err = queryAddress(address, serverConn)
+			}
+			if err == nil {
+				var client *a2s.Client
+				if client, err = dialClient(address, serverConn); err == nil {
+					o.result, o.rows, err = query(client, target)
+					closeClient(client)
+				}
+			}
+			o.err = err
+			if err == nil && !match(filterServer(o.result)) {
+				o.skip = true
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			failed = failed || err != nil
+			outcomes[i] = o
+
+			if formatter.ShouldUseJSON() && !o.skip {
+				line := batchResult{Address: target, Result: o.result}
+				if err != nil {
+					line.Error = err.Error()
+				}
+				formatter.PrintLine(line)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !formatter.ShouldUseJSON() {
+		t := formatter.NewTable()
+		t.AppendHeader(append(append(table.Row{"Address"}, header...), "Error"))
+
+		for i, o := range outcomes {
+			if o.skip {
+				continue
+			}
+
+			errText := ""
+			if o.err != nil {
+				errText = o.err.Error()
+			}
+
+			rows := o.rows
+			if len(rows) == 0 {
+				rows = []table.Row{{}}
+			}
+			for _, row := range rows {
+				for len(row) < len(header) {
+					row = append(row, "")
+				}
+				t.AppendRow(append(append(table.Row{targets[i]}, row...), errText))
+			}
+		}
+
+		formatter.PrintTable(t)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// compileFilter returns the predicate of a --filter expression, matching all servers if it is empty.
+func compileFilter(expr string) func(server filter.Server) bool {
+	if expr == "" {
+		return func(filter.Server) bool { return true }
+	}
+
+	f, err := filter.Compile(expr)
+	if err != nil {
+		fatalf("Invalid filter: %s", err)
+	}
+
+	return f.Match
+}
+
+// filterServer returns the data of a batch query result to evaluate --filter against,
+// fields of data not queried by the command are missing.
+func filterServer(result any) filter.Server {
+	switch result := result.(type) {
+	case infoView:
+		return filter.Server{Info: result.Info}
+	case []a2s.Player:
+		return filter.Server{Players: result}
+	case allView:
+		server := filterServer(result.Rules)
+		server.Info, server.Players = result.Info.Info, result.Players
+		return server
+	case *a3sb.Rules:
+		return filter.Server{Rules: result}
+	case map[string]string:
+		return filter.Server{RawRules: result}
+	case map[string]any:
+		rules := make(map[string]string, len(result))
+		for name, value := range result {
+			rules[name] = fmt.Sprint(value)
+		}
+		return filter.Server{RawRules: rules}
+	}
+
+	return filter.Server{}
+}
+
+// isPort reports whether value is a port number.
+func isPort(value string) bool {
+	port, err := strconv.ParseUint(value, 10, 16)
+	return err == nil && port > 0
+}
+
+// stdinPiped reports whether stdin is a pipe or file rather than a terminal.
+func stdinPiped() bool {
+	stat, err := os.Stdin.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice == 0
+}
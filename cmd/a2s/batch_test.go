@@ -0,0 +1,108 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+)
+
+func TestReadTargets(t *testing.T) {
+	input := `
+# production
+203.0.113.10:27016
+203.0.113.11 27016
+
+  203.0.113.12:2303  # comment
+example.com
+`
+	want := []string{"203.0.113.10:27016", "203.0.113.11:27016", "203.0.113.12:2303", "example.com"}
+
+	if got := readTargets(strings.NewReader(input)); !slices.Equal(got, want) {
+		t.Errorf("readTargets() = %v, want %v", got, want)
+	}
+}
+
+func TestTargetArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    TargetArgs
+		batch   BatchOptions
+		targets []string
+		single  bool
+	}{
+		{
+			name:    "host and port",
+			args:    TargetArgs{Host: "203.0.113.10", Port: "27016"},
+			single:  true,
+			targets: []string{"203.0.113.10:27016"},
+		},
+		{
+			name:    "host with port",
+			args:    TargetArgs{Host: "203.0.113.10:27016"},
+			single:  true,
+			targets: []string{"203.0.113.10:27016"},
+		},
+		{
+			name:    "two servers",
+			args:    TargetArgs{Host: "203.0.113.10:27016", Port: "203.0.113.11:27016"},
+			targets: []string{"203.0.113.10:27016", "203.0.113.11:27016"},
+		},
+		{
+			name:    "many servers",
+			args:    TargetArgs{Host: "203.0.113.10", Port: "27016", More: []string{"203.0.113.11:27016", "203.0.113.12:27016"}},
+			targets: []string{"203.0.113.10:27016", "203.0.113.11:27016", "203.0.113.12:27016"},
+		},
+		{
+			name:    "filter",
+			args:    TargetArgs{Host: "203.0.113.10:27016"},
+			batch:   BatchOptions{Filter: "players > 0"},
+			targets: []string{"203.0.113.10:27016"},
+		},
+		{
+			name:    "targets file",
+			args:    TargetArgs{Host: "203.0.113.10:27016"},
+			batch:   BatchOptions{TargetsFile: "/dev/null"},
+			targets: []string{"203.0.113.10:27016"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, single := tt.args.server(tt.batch); single != tt.single {
+				t.Errorf("server() single = %v, want %v", single, tt.single)
+			}
+			if got := tt.args.targets(tt.batch); !slices.Equal(got, tt.targets) {
+				t.Errorf("targets() = %v, want %v", got, tt.targets)
+			}
+		})
+	}
+}
+
+func TestFilterServer(t *testing.T) {
+	info := &a2s.Info{Name: "DayZ", Players: 12}
+	players := []a2s.Player{{Name: "Survivor"}}
+	rules := &a3sb.Rules{Mods: []a3sb.Mod{{ID: 1559212036}}}
+
+	tests := []struct {
+		result any
+		expr   string
+		want   bool
+	}{
+		{newInfoView(info), "players >= 10", true},
+		{players, `"Survivor" in players.names`, true},
+		{players, "players >= 10", false},
+		{rules, "mods.count == 1", true},
+		{map[string]string{"sv_gravity": "800"}, "rules.sv_gravity == 800", true},
+		{allView{Info: newInfoView(info), Players: players, Rules: map[string]any{"deathmatch": true}}, `players == 12 && players.count == 1 && rules.deathmatch == "true"`, true},
+		{allView{Info: newInfoView(info), Rules: rules}, "mods.count == 1 && players == 12", true},
+	}
+
+	for _, tt := range tests {
+		if got := compileFilter(tt.expr)(filterServer(tt.result)); got != tt.want {
+			t.Errorf("%s on %T = %t, want %t", tt.expr, tt.result, got, tt.want)
+		}
+	}
+}
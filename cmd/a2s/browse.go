@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/discovery"
+	"github.com/woozymasta/a2s/pkg/webapi"
+	"golang.org/x/term"
+)
+
+// browseTarget is a server of the browser with its connection options.
+type browseTarget struct {
+	conn    ConnectionOptions
+	address string
+}
+
+// browseResult is a finished background query.
+type browseResult struct {
+	err     error
+	all     *a3sb.All
+	address string
+}
+
+func executeBrowse(cmd *BrowseCommand) {
+	stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(stdin) || !term.IsTerminal(stdout) {
+		fatal("Browse requires an interactive terminal")
+	}
+
+	targets := browseTargets(cmd)
+	if len(targets) == 0 {
+		fatal("No servers to browse, pass servers, --master, --scan or add servers to the config")
+	}
+
+	model := &browseModel{}
+	conns := make(map[string]ConnectionOptions, len(targets))
+	for _, target := range targets {
+		model.servers = append(model.servers, &browseServer{Address: target.address})
+		conns[target.address] = target.conn
+	}
+
+	state, err := term.MakeRaw(stdin)
+	if err != nil {
+		fatalf("Failed to set terminal raw mode: %s", err)
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	restore := func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		_ = term.Restore(stdin, state)
+	}
+	defer restore()
+
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- slices.Clone(buf[:n])
+		}
+	}()
+
+	results := make(chan browseResult)
+	pending := 0
+	refresh := func() {
+		if pending > 0 {
+			return
+		}
+		pending = len(model.servers)
+		model.refreshed = time.Now()
+
+		go queryBrowse(targets, cmd.Parallel, results)
+	}
+
+	draw := func() {
+		width, height := terminalSize(stdout)
+
+		lines := model.render(width, height)
+		var b strings.Builder
+		b.WriteString("\x1b[H")
+		for i, line := range lines {
+			b.WriteString(line)
+			b.WriteString("\x1b[K")
+			if i < len(lines)-1 {
+				b.WriteString("\r\n")
+			}
+		}
+		fmt.Print(b.String())
+	}
+
+	ticker := time.NewTicker(cmd.Refresh)
+	defer ticker.Stop()
+	redraw := time.NewTicker(500 * time.Millisecond)
+	defer redraw.Stop()
+
+	fmt.Print("\x1b[2J")
+	refresh()
+	draw()
+
+	for {
+		select {
+		case input, ok := <-keys:
+			if !ok {
+				return
+			}
+
+			_, height := terminalSize(stdout)
+			for _, key := range parseKeys(input) {
+				if !model.editing && key == "r" {
+					refresh()
+					continue
+				}
+				if model.key(key, max(1, height/2)) {
+					return
+				}
+			}
+
+		case result := <-results:
+			pending--
+			model.update(result.address, result.all, result.err, time.Now())
+
+		case <-ticker.C:
+			refresh()
+
+		case <-redraw.C:
+			// Redraw to follow terminal resizes
+		}
+
+		draw()
+	}
+}
+
+// terminalSize returns the terminal size, 80x24 if unknown.
+func terminalSize(fd int) (int, int) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+
+	return width, height
+}
+
+// queryBrowse queries all targets in the background with limited concurrency
+// and sends the results to the channel.
+func queryBrowse(targets []browseTarget, parallel int, results chan<- browseResult) {
+	jobs := make(chan browseTarget)
+	var wg sync.WaitGroup
+	for range max(1, parallel) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range jobs {
+				result := browseResult{address: target.address}
+				result.all, result.err = queryBrowseServer(target)
+				results <- result
+			}
+		}()
+	}
+
+	for _, target := range targets {
+		jobs <- target
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// queryBrowseServer queries info, players and rules of a single server in one round trip.
+func queryBrowseServer(target browseTarget) (*a3sb.All, error) {
+	client, err := dialClient(target.address, target.conn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	return (&a3sb.Client{Client: client}).GetAll()
+}
+
+// browseTargets collects servers from arguments, the master server and scanned networks,
+// all config servers are used without any source.
+func browseTargets(cmd *BrowseCommand) []browseTarget {
+	addresses := cmd.Args.Servers
+	if len(addresses) == 0 && cmd.Master == "" && len(cmd.Scan) == 0 && config != nil {
+		for name := range config.Servers {
+			addresses = append(addresses, "@"+name)
+		}
+		slices.Sort(addresses)
+	}
+
+	var targets []browseTarget
+	seen := make(map[string]bool)
+	add := func(address string, conn ConnectionOptions) {
+		if !seen[address] {
+			seen[address] = true
+			targets = append(targets, browseTarget{address: address, conn: conn})
+		}
+	}
+
+	for _, target := range config.expand(addresses) {
+		address, conn, err := config.server(target, cmd.ConnectionOptions)
+		if err != nil {
+			fatal(err)
+		}
+		if address, err = queryAddress(address, conn); err != nil {
+			fatal(err)
+		}
+		add(address, conn)
+	}
+
+	// Servers found by the master server and scans already are query addresses
+	conn := cmd.ConnectionOptions
+	conn.GamePort = false
+
+	if cmd.Master != "" {
+		if cmd.Key == "" {
+			fatal("Steam Web API key must be provided with --key or STEAM_API_KEY for --master")
+		}
+
+		fmt.Fprintln(os.Stderr, "Loading server list from Steam Web API ...")
+		client := &webapi.Client{
+			HTTPClient: &http.Client{Timeout: 30 * time.Second},
+			Key:        cmd.Key,
+			Limit:      cmd.Limit,
+		}
+		servers, err := client.GetServerList(cmd.Master)
+		if err != nil {
+			fatalf("Failed to get server list: %s", err)
+		}
+		for _, server := range servers {
+			add(server.Addr, conn)
+		}
+	}
+
+	if len(cmd.Scan) > 0 {
+		networks := make([]netip.Prefix, 0, len(cmd.Scan))
+		for _, value := range cmd.Scan {
+			network, err := discovery.ParseNetwork(value)
+			if err != nil {
+				fatalf("Failed to parse network: %s", err)
+			}
+			networks = append(networks, network)
+		}
+
+		ports, err := discovery.ParsePorts(cmd.Ports)
+		if err != nil {
+			fatalf("Failed to parse ports: %s", err)
+		}
+
+		fmt.Fprintln(os.Stderr, "Scanning networks ...")
+		results, err := discovery.Scan(discovery.ScanOptions{
+			Networks: networks,
+			Ports:    ports,
+			Rate:     500,
+		})
+		if err != nil {
+			fatalf("Failed to scan: %s", err)
+		}
+		for _, result := range results {
+			add(result.Address, conn)
+		}
+	}
+
+	return targets
+}
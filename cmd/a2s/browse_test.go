@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+)
+
+func testBrowseModel() *browseModel {
+	server := func(address, name, mapName string, players uint8, ping time.Duration) *browseServer {
+		return &browseServer{Address: address, All: &a3sb.All{
+			Info: &a2s.Info{Name: name, Map: mapName, Players: players, MaxPlayers: 60, Ping: ping},
+		}}
+	}
+
+	return &browseModel{servers: []*browseServer{
+		server("127.0.0.1:2303", "Bravo", "chernarusplus", 10, 40*time.Millisecond),
+		{Address: "127.0.0.1:2403", Err: errors.New("timeout")},
+		server("127.0.0.1:2503", "alpha", "enoch", 30, 20*time.Millisecond),
+		server("127.0.0.1:2603", "Charlie", "deerisle", 0, 80*time.Millisecond),
+	}}
+}
+
+func browseAddresses(servers []*browseServer) []string {
+	addresses := make([]string, 0, len(servers))
+	for _, server := range servers {
+		addresses = append(addresses, server.Address)
+	}
+	return addresses
+}
+
+func TestBrowseSort(t *testing.T) {
+	m := testBrowseModel()
+
+	want := []string{"127.0.0.1:2503", "127.0.0.1:2303", "127.0.0.1:2603", "127.0.0.1:2403"}
+	if got := browseAddresses(m.visible()); !slices.Equal(got, want) {
+		t.Errorf("sort by name = %v, want %v", got, want)
+	}
+
+	// Name, Map, Players
+	m.key("s", 10)
+	m.key("s", 10)
+	m.key("S", 10)
+	want = []string{"127.0.0.1:2503", "127.0.0.1:2303", "127.0.0.1:2603", "127.0.0.1:2403"}
+	if got := browseAddresses(m.visible()); !slices.Equal(got, want) {
+		t.Errorf("sort by players reversed = %v, want %v", got, want)
+	}
+
+	m.key("s", 10)
+	m.key("S", 10)
+	want = []string{"127.0.0.1:2503", "127.0.0.1:2303", "127.0.0.1:2603", "127.0.0.1:2403"}
+	if got := browseAddresses(m.visible()); !slices.Equal(got, want) {
+		t.Errorf("sort by ping = %v, want %v", got, want)
+	}
+}
+
+func TestBrowseFilter(t *testing.T) {
+	m := testBrowseModel()
+
+	for _, key := range []string{"/", "E", "n", "x", "backspace", "enter"} {
+		m.key(key, 10)
+	}
+	if m.filter != "En" || m.editing {
+		t.Fatalf("filter = %q, editing %t", m.filter, m.editing)
+	}
+
+	// "enoch" map and "deerisle" does not match, names are matched too
+	if got := browseAddresses(m.visible()); !slices.Equal(got, []string{"127.0.0.1:2503"}) {
+		t.Errorf("filtered = %v", got)
+	}
+
+	m.key("esc", 10)
+	if m.filter != "" {
+		t.Errorf("esc did not clear filter %q", m.filter)
+	}
+}
+
+func TestBrowseSelection(t *testing.T) {
+	m := testBrowseModel()
+
+	// The first key selects the first row
+	m.key("down", 10)
+	if m.key("down", 10); m.selected != "127.0.0.1:2303" {
+		t.Errorf("selected = %s", m.selected)
+	}
+
+	// Selection follows the server when the order changes
+	m.key("S", 10)
+	if m.key("up", 10); m.selected != "127.0.0.1:2603" {
+		t.Errorf("selected after reverse = %s", m.selected)
+	}
+
+	m.key("end", 10)
+	if m.selected != "127.0.0.1:2403" {
+		t.Errorf("selected at end = %s", m.selected)
+	}
+	if m.key("pgdn", 10); m.selected != "127.0.0.1:2403" {
+		t.Errorf("selected past end = %s", m.selected)
+	}
+
+	if !m.key("q", 10) {
+		t.Error("q does not quit")
+	}
+}
+
+func TestBrowseUpdate(t *testing.T) {
+	m := testBrowseModel()
+	now := time.Now()
+
+	m.update("127.0.0.1:2303", nil, errors.New("timeout"), now)
+	server := m.servers[0]
+	if server.Err == nil || server.All == nil || !server.Updated.Equal(now) {
+		t.Errorf("failed refresh lost previous data: %+v", server)
+	}
+
+	all := &a3sb.All{Info: &a2s.Info{Name: "Delta"}}
+	if m.update("127.0.0.1:2403", all, nil, now); m.servers[1].All != all || m.servers[1].Err != nil {
+		t.Errorf("update not stored: %+v", m.servers[1])
+	}
+}
+
+func TestBrowseRender(t *testing.T) {
+	m := testBrowseModel()
+	m.servers[0].All.Players = []a2s.Player{{Name: "Survivor", Score: 3}}
+	m.key("down", 10)
+	m.key("down", 10)
+	m.key("enter", 10)
+
+	lines := m.render(100, 20)
+	if len(lines) != 20 {
+		t.Fatalf("render lines = %d, want 20", len(lines))
+	}
+
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{"3/4 servers up", "alpha", "Bravo", "[Players]", "Survivor", "timeout"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen does not contain %q:\n%s", want, screen)
+		}
+	}
+
+	m.key("tab", 10)
+	m.key("tab", 10)
+	if screen := strings.Join(m.render(100, 20), "\n"); !strings.Contains(screen, "not an Arma 3 or DayZ server") {
+		t.Errorf("mods tab:\n%s", screen)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("\x1b[Aq\x1b[6~\r\t/ж\x7f\x1b\x03"))
+	want := []string{"up", "q", "pgdn", "enter", "tab", "/", "ж", "backspace", "esc", "ctrl+c"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseKeys() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/keywords"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+// ANSI sequences of the browser screen.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiInverse = "\x1b[7m"
+	ansiFaint   = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+)
+
+// browseServer is a server of the browser with its last query result.
+type browseServer struct {
+	Updated time.Time // Time of the last query, zero if not queried yet
+	Err     error     // Error of the last query
+	All     *a3sb.All // Last received info, players and rules
+	Address string    // Query address
+}
+
+// browseColumn is a column of the server list, columns are also sort keys.
+type browseColumn struct {
+	value func(s *browseServer) string
+	less  func(a, b *browseServer) int
+	title string
+	width int // Fixed width, 0 for the flexible column
+}
+
+// browseColumns are the columns of the server list.
+var browseColumns = []browseColumn{
+	{title: "Name", value: func(s *browseServer) string { return s.All.Info.Name }, less: compareString(func(s *browseServer) string { return strings.ToLower(s.All.Info.Name) })},
+	{title: "Map", width: 16, value: func(s *browseServer) string { return s.All.Info.Map }, less: compareString(func(s *browseServer) string { return strings.ToLower(s.All.Info.Map) })},
+	{title: "Players", width: 9, value: func(s *browseServer) string {
+		return fmt.Sprintf("%d/%d", s.All.Info.Players, s.All.Info.MaxPlayers)
+	}, less: compareInt(func(s *browseServer) int { return int(s.All.Info.Players) })},
+	{title: "Ping", width: 6, value: func(s *browseServer) string { return strconv.FormatInt(s.All.Info.Ping.Milliseconds(), 10) }, less: compareInt(func(s *browseServer) int { return int(s.All.Info.Ping) })},
+	{title: "Mods", width: 5, value: func(s *browseServer) string {
+		if s.All.Rules == nil {
+			return "-"
+		}
+		return strconv.Itoa(len(s.All.Rules.Mods))
+	}, less: compareInt(func(s *browseServer) int { return modCount(s) })},
+	{title: "Pass", width: 5, value: func(s *browseServer) string { return yesNo(s.All.Info.Visibility) }, less: compareString(func(s *browseServer) string { return yesNo(s.All.Info.Visibility) })},
+	{title: "BE", width: 4, value: func(s *browseServer) string { return battlEye(s) }, less: compareString(battlEye)},
+	{title: "Address", width: 22, value: func(s *browseServer) string { return s.Address }, less: compareString(func(s *browseServer) string { return s.Address })},
+}
+
+// browseTabs are the tabs of the detail pane.
+var browseTabs = []string{"Players", "Rules", "Mods"}
+
+// browseModel is the state of the server browser, independent of the terminal.
+type browseModel struct {
+	refreshed time.Time
+	servers   []*browseServer
+	selected  string // Address of the selected server
+	filter    string
+	input     string // Filter being typed
+	sortCol   int
+	offset    int // First visible row of the list
+	tab       int
+	editing   bool // Filter input mode
+	reverse   bool
+	detail    bool // Detail pane is shown
+}
+
+// update stores the query result of the server at address. Previous data is kept
+// if nothing was received, partial data replaces it.
+func (m *browseModel) update(address string, all *a3sb.All, err error, now time.Time) {
+	for _, server := range m.servers {
+		if server.Address != address {
+			continue
+		}
+
+		server.Updated, server.Err = now, err
+		if all != nil && all.Info != nil {
+			server.All = all
+		}
+	}
+}
+
+// visible returns servers matching the filter in sort order. Servers without info are last.
+func (m *browseModel) visible() []*browseServer {
+	filter := strings.ToLower(m.filter)
+	servers := make([]*browseServer, 0, len(m.servers))
+	for _, server := range m.servers {
+		if filter == "" || strings.Contains(strings.ToLower(server.Address), filter) ||
+			(server.All != nil && server.All.Info != nil && (strings.Contains(strings.ToLower(server.All.Info.Name), filter) ||
+				strings.Contains(strings.ToLower(server.All.Info.Map), filter))) {
+			servers = append(servers, server)
+		}
+	}
+
+	column := browseColumns[m.sortCol]
+	slices.SortStableFunc(servers, func(a, b *browseServer) int {
+		aOK, bOK := hasInfo(a), hasInfo(b)
+		switch {
+		case !aOK || !bOK:
+			return compareBool(bOK, aOK)
+		case m.reverse:
+			return column.less(b, a)
+		default:
+			return column.less(a, b)
+		}
+	})
+
+	return servers
+}
+
+// key applies a key press and reports whether the browser should quit.
+// Keys are runes or names of special keys: up, down, pgup, pgdn, home, end, enter, esc, tab, backspace.
+func (m *browseModel) key(k string, page int) bool {
+	if m.editing {
+		switch k {
+		case "enter":
+			m.filter, m.editing = m.input, false
+		case "esc":
+			m.editing = false
+		case "backspace":
+			if runes := []rune(m.input); len(runes) > 0 {
+				m.input = string(runes[:len(runes)-1])
+			}
+		default:
+			if len([]rune(k)) == 1 {
+				m.input += k
+			}
+		}
+		return false
+	}
+
+	servers := m.visible()
+	index := slices.IndexFunc(servers, func(s *browseServer) bool { return s.Address == m.selected })
+	if index < 0 {
+		// The first row is selected until the selection is moved
+		index = 0
+		if k == "up" || k == "k" || k == "down" || k == "j" {
+			k = ""
+		}
+	}
+
+	switch k {
+	case "q", "ctrl+c":
+		return true
+	case "up", "k":
+		index--
+	case "down", "j":
+		index++
+	case "pgup":
+		index -= page
+	case "pgdn":
+		index += page
+	case "home", "g":
+		index = 0
+	case "end", "G":
+		index = len(servers) - 1
+	case "enter":
+		m.detail = !m.detail
+	case "tab":
+		m.tab = (m.tab + 1) % len(browseTabs)
+	case "s":
+		m.sortCol = (m.sortCol + 1) % len(browseColumns)
+	case "S":
+		m.reverse = !m.reverse
+	case "/":
+		m.editing, m.input = true, m.filter
+	case "esc":
+		if m.detail {
+			m.detail = false
+		} else {
+			m.filter = ""
+		}
+	}
+
+	if len(servers) > 0 {
+		m.selected = servers[max(0, min(index, len(servers)-1))].Address
+	}
+
+	return false
+}
+
+// render returns the screen of width and height as lines.
+func (m *browseModel) render(width, height int) []string {
+	servers := m.visible()
+	index := max(0, slices.IndexFunc(servers, func(s *browseServer) bool { return s.Address == m.selected }))
+
+	listHeight := height - 3
+	if m.detail {
+		listHeight = (height - 3) / 2
+	}
+	listHeight = max(1, listHeight)
+
+	// Keep the selected row visible
+	if index < m.offset {
+		m.offset = index
+	}
+	if index >= m.offset+listHeight {
+		m.offset = index - listHeight + 1
+	}
+	m.offset = max(0, min(m.offset, len(servers)-listHeight))
+
+	up := 0
+	for _, server := range m.servers {
+		if hasInfo(server) && server.Err == nil {
+			up++
+		}
+	}
+
+	order := "↑"
+	if m.reverse {
+		order = "↓"
+	}
+	title := fmt.Sprintf(" a2s browse  %d/%d servers up  sort: %s %s", up, len(m.servers), strings.ToLower(browseColumns[m.sortCol].title), order)
+	if m.filter != "" {
+		title += fmt.Sprintf("  filter: %q (%d)", m.filter, len(servers))
+	}
+	if !m.refreshed.IsZero() {
+		title += "  refreshed " + m.refreshed.Format(time.TimeOnly)
+	}
+
+	lines := make([]string, 0, height)
+	lines = append(lines, ansiInverse+fit(title, width)+ansiReset)
+	lines = append(lines, fit(m.row(nil, width), width))
+
+	for i := m.offset; i < m.offset+listHeight; i++ {
+		if i >= len(servers) {
+			lines = append(lines, "")
+			continue
+		}
+
+		server := servers[i]
+		line := fit(m.row(server, width), width)
+		switch {
+		case i == index:
+			line = ansiInverse + line + ansiReset
+		case server.Err != nil:
+			line = ansiRed + line + ansiReset
+		case !hasInfo(server):
+			line = ansiFaint + line + ansiReset
+		}
+		lines = append(lines, line)
+	}
+
+	if m.detail && index < len(servers) {
+		detailHeight := height - len(lines) - 2
+		lines = append(lines, ansiInverse+fit(m.tabs(servers[index]), width)+ansiReset)
+		for _, line := range padLines(detailLines(servers[index], m.tab), detailHeight) {
+			lines = append(lines, fit(line, width))
+		}
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+
+	if m.editing {
+		lines = append(lines, fit("/"+m.input+"█", width))
+	} else {
+		lines = append(lines, ansiFaint+fit("↑↓ move  enter details  tab switch  s sort  S reverse  / filter  r refresh  q quit", width)+ansiReset)
+	}
+
+	return lines
+}
+
+// row returns the list row of the server, the header if server is nil.
+func (m *browseModel) row(server *browseServer, width int) string {
+	fixed := 0
+	for _, column := range browseColumns {
+		fixed += column.width + 1
+	}
+	flex := max(10, width-fixed-1)
+
+	var b strings.Builder
+	for i, column := range browseColumns {
+		w := column.width
+		if w == 0 {
+			w = flex
+		}
+
+		var value string
+		switch {
+		case server == nil:
+			value = column.title
+			if i == m.sortCol {
+				value += "*"
+			}
+		case hasInfo(server):
+			value = column.value(server)
+		case column.title == "Address":
+			value = server.Address
+		case column.title == "Name" && server.Err != nil:
+			value = server.Err.Error()
+		case column.title == "Name":
+			value = "querying ..."
+		}
+
+		b.WriteString(" ")
+		b.WriteString(fit(value, w))
+	}
+
+	return b.String()
+}
+
+// tabs returns the title line of the detail pane.
+func (m *browseModel) tabs(server *browseServer) string {
+	var b strings.Builder
+	for i, tab := range browseTabs {
+		if i == m.tab {
+			fmt.Fprintf(&b, " [%s]", tab)
+		} else {
+			fmt.Fprintf(&b, "  %s ", tab)
+		}
+	}
+
+	b.WriteString("  " + server.Address)
+	if !server.Updated.IsZero() {
+		b.WriteString("  updated " + server.Updated.Format(time.TimeOnly))
+	}
+
+	return b.String()
+}
+
+// detailLines returns lines of the detail tab of the server.
+func detailLines(server *browseServer, tab int) []string {
+	if server.All == nil {
+		if server.Err != nil {
+			return []string{" " + server.Err.Error()}
+		}
+		return []string{" querying ..."}
+	}
+
+	var lines []string
+	switch browseTabs[tab] {
+	case "Players":
+		for _, player := range server.All.Players {
+			lines = append(lines, fmt.Sprintf(" %6d  %10s  %s", player.Score, player.Duration.Round(time.Second), player.Name))
+		}
+		if len(lines) == 0 {
+			lines = append(lines, " no players")
+		}
+
+	case "Rules":
+		_, rows := rulesResult(server.All.RawRules, server.All.Rules)
+		for _, row := range rows {
+			lines = append(lines, fmt.Sprintf(" %s = %s", row[0], row[1]))
+		}
+		if len(lines) == 0 {
+			lines = append(lines, " no rules")
+		}
+
+	case "Mods":
+		if server.All.Rules == nil {
+			return []string{" not an Arma 3 or DayZ server"}
+		}
+		for _, mod := range server.All.Rules.Mods {
+			lines = append(lines, fmt.Sprintf(" %12d  %s", mod.ID, mod.DisplayName()))
+		}
+		if len(lines) == 0 {
+			lines = append(lines, " no mods")
+		}
+	}
+
+	if server.Err != nil {
+		lines = append([]string{ansiRed + " " + server.Err.Error() + ansiReset}, lines...)
+	}
+
+	return lines
+}
+
+// padLines returns exactly n lines, truncated or padded with empty ones.
+func padLines(lines []string, n int) []string {
+	n = max(0, n)
+	if len(lines) > n {
+		return lines[:n]
+	}
+
+	return append(lines, make([]string, n-len(lines))...)
+}
+
+// fit truncates or pads text to the display width.
+func fit(s string, width int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < ' ' {
+			return ' '
+		}
+		return r
+	}, s)
+
+	if text.StringWidthWithoutEscSequences(s) > width {
+		s = text.Trim(s, width)
+	}
+
+	return text.Pad(s, width, ' ')
+}
+
+// hasInfo reports whether A2S_INFO of the server was received.
+func hasInfo(s *browseServer) bool {
+	return s.All != nil && s.All.Info != nil
+}
+
+// modCount returns the number of A3SB mods, -1 for other games.
+func modCount(s *browseServer) int {
+	if s.All.Rules == nil {
+		return -1
+	}
+	return len(s.All.Rules.Mods)
+}
+
+// battlEye returns whether the Arma 3 or DayZ server is protected with BattlEye, "-" for other games.
+func battlEye(s *browseServer) string {
+	switch s.All.Info.ID {
+	case appid.Arma3.Uint64():
+		return yesNo(keywords.ParseArma3(s.All.Info.Keywords).BattlEye)
+	case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
+		return yesNo(keywords.ParseDayZ(s.All.Info.Keywords).BattlEye)
+	}
+	return "-"
+}
+
+// yesNo formats a flag as a list cell.
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// compareString returns a comparison of servers by a string value.
+func compareString(value func(s *browseServer) string) func(a, b *browseServer) int {
+	return func(a, b *browseServer) int { return cmp.Compare(value(a), value(b)) }
+}
+
+// compareInt returns a comparison of servers by a number.
+func compareInt(value func(s *browseServer) int) func(a, b *browseServer) int {
+	return func(a, b *browseServer) int { return cmp.Compare(value(a), value(b)) }
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+// terminalKeys are names of escape sequences sent by terminals for special keys.
+var terminalKeys = map[string]string{
+	"\x1b[A": "up", "\x1bOA": "up",
+	"\x1b[B": "down", "\x1bOB": "down",
+	"\x1b[5~": "pgup", "\x1b[6~": "pgdn",
+	"\x1b[H": "home", "\x1bOH": "home", "\x1b[1~": "home",
+	"\x1b[F": "end", "\x1bOF": "end", "\x1b[4~": "end",
+}
+
+// parseKeys splits terminal input in raw mode into key names accepted by browseModel.key.
+func parseKeys(input []byte) []string {
+	var keys []string
+	for s := string(input); s != ""; {
+		if s[0] == 0x1b && len(s) > 1 {
+			if end := strings.IndexAny(s[1:], "ABCDHF~"); end >= 0 && end < 4 {
+				if name, ok := terminalKeys[s[:end+2]]; ok {
+					keys = append(keys, name)
+				}
+				s = s[end+2:]
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+
+		switch r {
+		case 0x1b:
+			keys = append(keys, "esc")
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case '\t':
+			keys = append(keys, "tab")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl+c")
+		default:
+			if r >= ' ' && r != utf8.RuneError {
+				keys = append(keys, string(r))
+			}
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/keywords"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+// Plugin states of Nagios and Icinga checks, also used as exit codes.
+const (
+	checkOK = iota
+	checkWarning
+	checkCritical
+	checkUnknown
+)
+
+// checkStates are names of plugin states by exit code.
+var checkStates = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// checkRange is a Nagios plugin threshold range: [@]start:end, "~" for negative infinity.
+// Values outside the range alert, or inside it if the range starts with "@".
+type checkRange struct {
+	text   string
+	start  float64
+	end    float64
+	inside bool
+}
+
+// parseRange parses a threshold range, nil for an empty value.
+func parseRange(value string) (*checkRange, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	r := &checkRange{text: value, end: math.Inf(1)}
+	spec := value
+	if strings.HasPrefix(spec, "@") {
+		r.inside, spec = true, spec[1:]
+	}
+
+	if spec == "" {
+		return nil, fmt.Errorf("Invalid threshold range %q", value)
+	}
+
+	start, end, isRange := strings.Cut(spec, ":")
+	if !isRange {
+		start, end = "0", spec
+	}
+
+	var err error
+	switch start {
+	case "~":
+		r.start = math.Inf(-1)
+	case "":
+	default:
+		if r.start, err = strconv.ParseFloat(start, 64); err != nil {
+			return nil, fmt.Errorf("Invalid threshold range %q", value)
+		}
+	}
+	if end != "" {
+		if r.end, err = strconv.ParseFloat(end, 64); err != nil {
+			return nil, fmt.Errorf("Invalid threshold range %q", value)
+		}
+	}
+	if r.start > r.end {
+		return nil, fmt.Errorf("Invalid threshold range %q, start is greater than end", value)
+	}
+
+	return r, nil
+}
+
+// alert reports whether the value raises an alert.
+func (r *checkRange) alert(value float64) bool {
+	if r == nil {
+		return false
+	}
+
+	outside := value < r.start || value > r.end
+	return outside != r.inside
+}
+
+// String returns the range as given, empty for nil.
+func (r *checkRange) String() string {
+	if r == nil {
+		return ""
+	}
+	return r.text
+}
+
+// checkThresholds are parsed thresholds of the check command.
+type checkThresholds struct {
+	warningPing, criticalPing       *checkRange
+	warningFill, criticalFill       *checkRange
+	warningBuild, criticalBuild     *checkRange
+	warningMap, criticalMap         string
+	warningVersion, criticalVersion string
+}
+
+// checkResult is the state, message and perfdata of a check.
+type checkResult struct {
+	message  string
+	perfdata string
+	state    int
+}
+
+// String returns the single plugin output line.
+func (r checkResult) String() string {
+	line := "A2S " + checkStates[r.state] + ": " + r.message
+	if r.perfdata != "" {
+		line += " | " + r.perfdata
+	}
+
+	return line
+}
+
+func executeCheck(cmd *CheckCommand) {
+	result := runCheck(cmd)
+	fmt.Println(result)
+	os.Exit(checkExitCode(result.state, cmd.HealthCheck))
+}
+
+// checkExitCode returns the exit code of the plugin state.
+func checkExitCode(state int, healthCheck bool) int {
+	if healthCheck {
+		// Docker treats any non-zero code as unhealthy and reserves code 2
+		if state == checkOK || state == checkWarning {
+			return 0
+		}
+		return 1
+	}
+
+	return state
+}
+
+// isCheck reports whether the check command is parsed, also after a parse error.
+func isCheck(p *flags.Parser) bool {
+	return p.Active != nil && p.Active.Name == "check"
+}
+
+// fatalUsage exits on usage and configuration errors found before the command runs,
+// the check command reports them as UNKNOWN plugin state.
+func fatalUsage(p *flags.Parser, opts *Options, format string, a ...any) {
+	if isCheck(p) {
+		fatalCheck(opts.Check.HealthCheck, format, a...)
+	}
+
+	fatalf(format, a...)
+}
+
+// fatalCheck prints the UNKNOWN plugin output and exits with its code.
+func fatalCheck(healthCheck bool, format string, a ...any) {
+	fmt.Println(checkResult{state: checkUnknown, message: fmt.Sprintf(format, a...)})
+	os.Exit(checkExitCode(checkUnknown, healthCheck))
+}
+
+// runCheck queries the server and evaluates thresholds, errors of arguments are UNKNOWN
+// and query failures are CRITICAL.
+func runCheck(cmd *CheckCommand) checkResult {
+	unknown := func(format string, a ...any) checkResult {
+		return checkResult{state: checkUnknown, message: fmt.Sprintf(format, a...)}
+	}
+
+	thresholds, err := cmd.thresholds()
+	if err != nil {
+		return unknown("%s", err)
+	}
+	if cmd.Args.Host == "" {
+		return unknown("Host must be provided")
+	}
+
+	address, conn, err := config.server(serverAddress(cmd.Args.Host, cmd.Args.Port), cmd.ConnectionOptions)
+	if err == nil {
+		address, err = queryAddress(address, conn)
+	}
+	if err != nil {
+		return unknown("%s", err)
+	}
+
+	client, err := dialClient(address, conn)
+	if err != nil {
+		return unknown("%s", err)
+	}
+	defer closeClient(client)
+
+	info, err := client.GetInfo()
+	if err != nil {
+		return checkResult{state: checkCritical, message: fmt.Sprintf("%s does not respond: %s", address, err)}
+	}
+
+	var rules *a3sb.Rules
+	if thresholds.warningBuild != nil || thresholds.criticalBuild != nil {
+		if !isA3SBGame(info.ID) {
+			return unknown("Required build is only available for Arma 3 and DayZ servers")
+		}
+		if rules, err = (&a3sb.Client{Client: client}).GetRules(info.ID); err != nil {
+			return checkResult{state: checkCritical, message: fmt.Sprintf("%s does not respond with A3SB rules: %s", address, err)}
+		}
+	}
+
+	return evaluateCheck(info, rules, thresholds)
+}
+
+// thresholds parses threshold options.
+func (cmd *CheckCommand) thresholds() (checkThresholds, error) {
+	t := checkThresholds{
+		warningMap:      cmd.WarningMap,
+		criticalMap:     cmd.CriticalMap,
+		warningVersion:  cmd.WarningVersion,
+		criticalVersion: cmd.CriticalVersion,
+	}
+
+	for _, pattern := range []string{t.warningVersion, t.criticalVersion} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return t, fmt.Errorf("Invalid version pattern %q", pattern)
+		}
+	}
+
+	var err error
+	for _, r := range []struct {
+		dst   **checkRange
+		value string
+	}{
+		{&t.warningPing, cmd.WarningPing},
+		{&t.criticalPing, cmd.CriticalPing},
+		{&t.warningFill, cmd.WarningFill},
+		{&t.criticalFill, cmd.CriticalFill},
+		{&t.warningBuild, cmd.WarningBuild},
+		{&t.criticalBuild, cmd.CriticalBuild},
+	} {
+		if *r.dst, err = parseRange(r.value); err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
+
+// evaluateCheck evaluates thresholds against the server info and optional A3SB rules.
+func evaluateCheck(info *a2s.Info, rules *a3sb.Rules, t checkThresholds) checkResult {
+	var (
+		state    = checkOK
+		problems []string
+	)
+	raise := func(level int, format string, a ...any) {
+		state = max(state, level)
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	// Range checks, critical first so that a value is reported once
+	rangeCheck := func(name string, value float64, unit string, warning, critical *checkRange) {
+		switch {
+		case critical.alert(value):
+			raise(checkCritical, "%s %s%s (critical %s)", name, strconv.FormatFloat(value, 'f', -1, 64), unit, critical)
+		case warning.alert(value):
+			raise(checkWarning, "%s %s%s (warning %s)", name, strconv.FormatFloat(value, 'f', -1, 64), unit, warning)
+		}
+	}
+
+	ping := float64(info.Ping.Milliseconds())
+	rangeCheck("ping", ping, " ms", t.warningPing, t.criticalPing)
+
+	fill := 0.0
+	if info.MaxPlayers > 0 {
+		fill = math.Round(float64(info.Players)/float64(info.MaxPlayers)*1000) / 10
+	}
+	rangeCheck("player fill", fill, "%", t.warningFill, t.criticalFill)
+
+	if rules != nil {
+		build := float64(rules.RequiredBuild)
+		if build == 0 && info.ID == appid.Arma3.Uint64() {
+			build = float64(keywords.ParseArma3(info.Keywords).RequiredBuildNo)
+		}
+		rangeCheck("required build", build, "", t.warningBuild, t.criticalBuild)
+	}
+
+	matchCheck := func(name, value, warning, critical string) {
+		switch {
+		case critical != "" && !matchPattern(critical, value):
+			raise(checkCritical, "%s %q, expected %q", name, value, critical)
+		case warning != "" && !matchPattern(warning, value):
+			raise(checkWarning, "%s %q, expected %q", name, value, warning)
+		}
+	}
+	matchCheck("map", info.Map, t.warningMap, t.criticalMap)
+	matchCheck("version", info.Version, t.warningVersion, t.criticalVersion)
+
+	message := fmt.Sprintf("%s on %s, %d/%d players, %d ms", checkText(info.Name), checkText(info.Map), info.Players, info.MaxPlayers, info.Ping.Milliseconds())
+	if len(problems) > 0 {
+		message = checkText(strings.Join(problems, ", ")) + " - " + message
+	}
+
+	perfdata := fmt.Sprintf("players=%d;;;0;%d max_players=%d;;;0; bots=%d;;;0; ping=%dms;%s;%s;0;",
+		info.Players, info.MaxPlayers, info.MaxPlayers, info.Bots, info.Ping.Milliseconds(), t.warningPing, t.criticalPing)
+
+	return checkResult{state: state, message: message, perfdata: perfdata}
+}
+
+// matchPattern reports whether the value matches the exact or glob pattern.
+func matchPattern(pattern, value string) bool {
+	ok, err := path.Match(pattern, value)
+	return pattern == value || (err == nil && ok)
+}
+
+// checkText strips color codes and the perfdata separator from text of the plugin output.
+func checkText(s string) string {
+	return strings.ReplaceAll(colorCodes.ReplaceAllString(s, ""), "|", "/")
+}
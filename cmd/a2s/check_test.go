@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		spec   string
+		alerts []float64
+		ok     []float64
+	}{
+		{spec: "10", alerts: []float64{-1, 11}, ok: []float64{0, 10}},
+		{spec: "10:", alerts: []float64{9}, ok: []float64{10, 1e9}},
+		{spec: "~:10", alerts: []float64{11}, ok: []float64{-1e9, 10}},
+		{spec: "10:20", alerts: []float64{9, 21}, ok: []float64{10, 20}},
+		{spec: "@10:20", alerts: []float64{10, 20}, ok: []float64{9, 21}},
+		{spec: "@0", alerts: []float64{0}, ok: []float64{1}},
+	}
+
+	for _, tt := range tests {
+		r, err := parseRange(tt.spec)
+		if err != nil {
+			t.Fatalf("parseRange(%q) error: %v", tt.spec, err)
+		}
+		for _, value := range tt.alerts {
+			if !r.alert(value) {
+				t.Errorf("%q does not alert on %v", tt.spec, value)
+			}
+		}
+		for _, value := range tt.ok {
+			if r.alert(value) {
+				t.Errorf("%q alerts on %v", tt.spec, value)
+			}
+		}
+	}
+
+	for _, spec := range []string{"x", "1:x", "20:10", "@"} {
+		if _, err := parseRange(spec); err == nil {
+			t.Errorf("parseRange(%q) no error", spec)
+		}
+	}
+	if r, err := parseRange(""); r != nil || err != nil || r.alert(1) {
+		t.Errorf("empty range = %v, %v", r, err)
+	}
+}
+
+func TestEvaluateCheck(t *testing.T) {
+	info := &a2s.Info{
+		Name:       "DayZ | EU",
+		Map:        "chernarusplus",
+		Version:    "1.27.159674",
+		ID:         appid.DayZ.Uint64(),
+		Players:    54,
+		MaxPlayers: 60,
+		Bots:       0,
+		Ping:       120 * time.Millisecond,
+	}
+	rules := &a3sb.Rules{RequiredBuild: 42500}
+	threshold := func(spec string) *checkRange {
+		r, err := parseRange(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		t       checkThresholds
+		state   int
+		message string
+	}{
+		{name: "no thresholds", state: checkOK, message: "DayZ / EU on chernarusplus, 54/60 players, 120 ms"},
+		{name: "ping warning", t: checkThresholds{warningPing: threshold("100"), criticalPing: threshold("250")}, state: checkWarning, message: "ping 120 ms (warning 100)"},
+		{name: "ping critical", t: checkThresholds{warningPing: threshold("50"), criticalPing: threshold("100")}, state: checkCritical, message: "ping 120 ms (critical 100)"},
+		{name: "fill", t: checkThresholds{warningFill: threshold("80")}, state: checkWarning, message: "player fill 90% (warning 80)"},
+		{name: "map", t: checkThresholds{criticalMap: "enoch", warningMap: "chernarusplus"}, state: checkCritical, message: `map "chernarusplus", expected "enoch"`},
+		{name: "version glob", t: checkThresholds{criticalVersion: "1.27.*"}, state: checkOK},
+		{name: "version", t: checkThresholds{warningVersion: "1.28.*"}, state: checkWarning, message: `version "1.27.159674", expected "1.28.*"`},
+		{name: "build", t: checkThresholds{criticalBuild: threshold("43000:")}, state: checkCritical, message: "required build 42500 (critical 43000:)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := evaluateCheck(info, rules, tt.t)
+			if result.state != tt.state {
+				t.Errorf("state = %s, want %s: %s", checkStates[result.state], checkStates[tt.state], result)
+			}
+			if !strings.Contains(result.message, tt.message) {
+				t.Errorf("message = %q, want %q", result.message, tt.message)
+			}
+		})
+	}
+
+	result := evaluateCheck(info, nil, checkThresholds{warningPing: threshold("100"), criticalPing: threshold("250")})
+	want := "A2S WARNING: ping 120 ms (warning 100) - DayZ / EU on chernarusplus, 54/60 players, 120 ms | " +
+		"players=54;;;0;60 max_players=60;;;0; bots=0;;;0; ping=120ms;100;250;0;"
+	if got := result.String(); got != want {
+		t.Errorf("output = %q\nwant %q", got, want)
+	}
+}
+
+func TestCheckUnknown(t *testing.T) {
+	result := runCheck(&CheckCommand{Args: ServerArgs{Host: "127.0.0.1:27016"}, ConnectionOptions: ConnectionOptions{Proxy: "bad://proxy"}})
+	if result.state != checkUnknown || !strings.Contains(result.message, "Invalid proxy") {
+		t.Errorf("invalid proxy result = %s, want UNKNOWN", result)
+	}
+
+	for _, tt := range []struct {
+		state, want int
+		healthCheck bool
+	}{
+		{checkOK, 0, false}, {checkWarning, 1, false}, {checkCritical, 2, false}, {checkUnknown, 3, false},
+		{checkOK, 0, true}, {checkWarning, 0, true}, {checkCritical, 1, true}, {checkUnknown, 1, true},
+	} {
+		if got := checkExitCode(tt.state, tt.healthCheck); got != tt.want {
+			t.Errorf("checkExitCode(%s, %v) = %d, want %d", checkStates[tt.state], tt.healthCheck, got, tt.want)
+		}
+	}
+}
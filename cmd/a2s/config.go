@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the CLI config file with named servers, groups of servers and default option values.
+//
+//	defaults:
+//	  format: table
+//	  timeout: 3
+//	  game: dayz
+//	servers:
+//	  dayz-eu1: {host: 203.0.113.10, port: 27016, game: dayz, timeout: 5}
+//	  dayz-eu2: {host: 203.0.113.11, port: 27016}
+//	groups:
+//	  dayz-eu: [dayz-eu1, dayz-eu2]
+type Config struct {
+	Servers  map[string]ServerConfig `yaml:"servers"`  // Named servers used as @name
+	Groups   map[string][]string     `yaml:"groups"`   // Groups of server names or addresses used as @group
+	Defaults map[string]any          `yaml:"defaults"` // Default values of options by long flag name, e.g. format or skip-info
+
+	explicit map[string]bool // Options set by flags or environment, not overridden by server values
+}
+
+// ServerConfig is a named server of the config file.
+type ServerConfig struct {
+	Host    string `yaml:"host"`              // Server host, may include the query port
+	Game    string `yaml:"game,omitempty"`    // Game type for rules, mods and keys, arma3 or dayz
+	Timeout int    `yaml:"timeout,omitempty"` // Query timeout in seconds
+	Port    uint16 `yaml:"port,omitempty"`    // Query port
+	Buffer  uint16 `yaml:"buffer,omitempty"`  // Read buffer size
+}
+
+// config is the loaded CLI config, nil if there is none.
+var config *Config
+
+// defaultConfigPath returns the default config file path, e.g. ~/.config/a2s/config.yaml.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "a2s", "config.yaml")
+}
+
+// loadConfig reads the config file at path. A missing file is not an error unless required.
+func loadConfig(path string, required bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !required && errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for name, server := range cfg.Servers {
+		if server.Host == "" {
+			return nil, fmt.Errorf("%s: server %s has no host", path, name)
+		}
+		if _, ok := cfg.Groups[name]; ok {
+			return nil, fmt.Errorf("%s: %s is both a server and a group", path, name)
+		}
+		if server.Game != "" {
+			if _, err := gameAppID(server.Game); err != nil {
+				return nil, fmt.Errorf("%s: server %s: %w", path, name, err)
+			}
+		}
+	}
+	if game, ok := cfg.Defaults["game"]; ok {
+		if _, err := gameAppID(fmt.Sprint(game)); err != nil {
+			return nil, fmt.Errorf("%s: defaults: %w", path, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// applyDefaults sets options of the command that were not given by flags or environment
+// to the config defaults. Defaults of options the command does not have are ignored.
+func (c *Config) applyDefaults(cmd *flags.Command) error {
+	c.explicit = make(map[string]bool)
+	for _, name := range []string{"timeout", "buffer-size", "game"} {
+		if option := cmd.FindOptionByLongName(name); option != nil && isExplicit(option) {
+			c.explicit[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(c.Defaults))
+	for name := range c.Defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		option := cmd.FindOptionByLongName(name)
+		if option == nil || isExplicit(option) {
+			continue
+		}
+
+		value := fmt.Sprint(c.Defaults[name])
+		if err := option.Set(&value); err != nil {
+			return fmt.Errorf("default %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// isExplicit reports whether the option was given as flag or environment variable.
+func isExplicit(option *flags.Option) bool {
+	if option.IsSet() && !option.IsSetDefault() {
+		return true
+	}
+
+	if option.EnvDefaultKey != "" {
+		_, ok := os.LookupEnv(option.EnvDefaultKey)
+		return ok
+	}
+
+	return false
+}
+
+// isGroup reports whether target is a @group of the config.
+func (c *Config) isGroup(target string) bool {
+	if c == nil || !strings.HasPrefix(target, "@") {
+		return false
+	}
+
+	_, ok := c.Groups[target[1:]]
+	return ok
+}
+
+// expand replaces @group targets with their members, other targets are kept.
+func (c *Config) expand(targets []string) []string {
+	expanded := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if !c.isGroup(target) {
+			expanded = append(expanded, target)
+			continue
+		}
+
+		for _, member := range c.Groups[target[1:]] {
+			if _, ok := c.Servers[strings.TrimPrefix(member, "@")]; ok {
+				member = "@" + strings.TrimPrefix(member, "@")
+			}
+			expanded = append(expanded, member)
+		}
+	}
+
+	return expanded
+}
+
+// server returns the address of a @name target and connection options with timeout and buffer
+// of the named server unless given by flags. Other targets are returned unchanged.
+func (c *Config) server(target string, conn ConnectionOptions) (string, ConnectionOptions, error) {
+	if !strings.HasPrefix(target, "@") {
+		return target, conn, nil
+	}
+
+	name := target[1:]
+	if c == nil {
+		return "", conn, fmt.Errorf("Unknown server %s, no config file found at %s", target, defaultConfigPath())
+	}
+	if c.isGroup(target) {
+		return "", conn, fmt.Errorf("Group %s can not be used here, a single server is expected", target)
+	}
+
+	server, ok := c.Servers[name]
+	if !ok {
+		return "", conn, fmt.Errorf("Unknown server %s", target)
+	}
+
+	if server.Timeout > 0 && !c.explicit["timeout"] {
+		conn.Timeout = server.Timeout
+	}
+	if server.Buffer > 0 && !c.explicit["buffer-size"] {
+		conn.Buffer = server.Buffer
+	}
+
+	if server.Port == 0 {
+		return server.Host, conn, nil
+	}
+
+	return serverAddress(server.Host, strconv.Itoa(int(server.Port))), conn, nil
+}
+
+// game returns the game of a @name target unless given by flags, game otherwise.
+func (c *Config) game(target, game string) string {
+	if c == nil || c.explicit["game"] || !strings.HasPrefix(target, "@") {
+		return game
+	}
+
+	if server, ok := c.Servers[target[1:]]; ok && server.Game != "" {
+		return server.Game
+	}
+
+	return game
+}
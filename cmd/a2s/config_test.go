@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jessevdk/go-flags"
+)
+
+const testConfig = `
+defaults:
+  format: md
+  timeout: 7
+  skip-info: true
+servers:
+  dayz-eu1: {host: 203.0.113.10, port: 27016, game: dayz, timeout: 5, buffer: 2048}
+  arma-eu1: {host: "203.0.113.20:2303"}
+groups:
+  eu: [dayz-eu1, "@arma-eu1", "203.0.113.30:27016"]
+`
+
+func writeConfig(t *testing.T, data string) *Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(path, true)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	return cfg
+}
+
+func TestConfigServers(t *testing.T) {
+	cfg := writeConfig(t, testConfig)
+
+	want := []string{"@dayz-eu1", "@arma-eu1", "203.0.113.30:27016", "203.0.113.40:27016"}
+	if got := cfg.expand([]string{"@eu", "203.0.113.40:27016"}); !slices.Equal(got, want) {
+		t.Errorf("expand() = %v, want %v", got, want)
+	}
+
+	address, conn, err := cfg.server("@dayz-eu1", ConnectionOptions{Timeout: 3, Buffer: 4096})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != "203.0.113.10:27016" || conn.Timeout != 5 || conn.Buffer != 2048 {
+		t.Errorf("server() = %s %+v", address, conn)
+	}
+
+	if address, _, _ := cfg.server("@arma-eu1", ConnectionOptions{}); address != "203.0.113.20:2303" {
+		t.Errorf("server() = %s, want host with port", address)
+	}
+	if _, _, err := cfg.server("@unknown", ConnectionOptions{}); err == nil {
+		t.Error("server() of unknown name succeeded")
+	}
+	if _, _, err := cfg.server("@eu", ConnectionOptions{}); err == nil {
+		t.Error("server() of group succeeded")
+	}
+
+	if game := cfg.game("@dayz-eu1", ""); game != "dayz" {
+		t.Errorf("game() = %q, want dayz", game)
+	}
+	if !cfg.isGroup("@eu") || cfg.isGroup("@dayz-eu1") || cfg.isGroup("eu") {
+		t.Error("isGroup() mismatch")
+	}
+
+	var none *Config
+	if address, _, err := none.server("203.0.113.1:27016", ConnectionOptions{}); err != nil || address != "203.0.113.1:27016" {
+		t.Errorf("nil config server() = %s, %v", address, err)
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	cfg := writeConfig(t, testConfig)
+
+	opts := &Options{}
+	p := flags.NewParser(opts, flags.Default)
+	if _, err := p.ParseArgs([]string{"rules", "@dayz-eu1", "-t", "9"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.applyDefaults(p.Active); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := opts.Rules
+	if cmd.Format != "md" || !cmd.SkipInfo {
+		t.Errorf("defaults not applied: format %q, skip-info %v", cmd.Format, cmd.SkipInfo)
+	}
+	if cmd.Timeout != 9 {
+		t.Errorf("timeout = %d, flag value 9 overridden", cmd.Timeout)
+	}
+
+	// Flags win over server values
+	if _, conn, _ := cfg.server("@dayz-eu1", cmd.ConnectionOptions); conn.Timeout != 9 || conn.Buffer != 2048 {
+		t.Errorf("server() connection = %+v", conn)
+	}
+}
+
+func TestConfigExplicitFlags(t *testing.T) {
+	cfg := writeConfig(t, testConfig)
+
+	opts := &Options{}
+	p := flags.NewParser(opts, flags.Default)
+	if _, err := p.ParseArgs([]string{"rules", "@dayz-eu1", "--timeout", "9", "-b", "1024", "-g", "arma3"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.applyDefaults(p.Active); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := opts.Rules
+	if _, conn, _ := cfg.server("@dayz-eu1", cmd.ConnectionOptions); conn.Timeout != 9 || conn.Buffer != 1024 {
+		t.Errorf("server() connection = %+v, want flags timeout 9 and buffer 1024", conn)
+	}
+	if game := cfg.game("@dayz-eu1", cmd.Game); game != "arma3" {
+		t.Errorf("game() = %q, want flag value arma3", game)
+	}
+}
+
+func TestLoadConfigUnknownGame(t *testing.T) {
+	for _, data := range []string{
+		"servers:\n  eu1: {host: 203.0.113.10, game: quake}\n",
+		"defaults:\n  game: quake\n",
+	} {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadConfig(path, true); err == nil {
+			t.Errorf("loadConfig(%q) accepted unknown game", data)
+		}
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.yaml")
+
+	if cfg, err := loadConfig(path, false); cfg != nil || err != nil {
+		t.Errorf("loadConfig() optional = %v, %v", cfg, err)
+	}
+	if _, err := loadConfig(path, true); err == nil {
+		t.Error("loadConfig() required missing file succeeded")
+	}
+}
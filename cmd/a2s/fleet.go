@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jessevdk/go-flags"
+	"github.com/woozymasta/a2s/pkg/fleet"
+)
+
+func executeFleet(cmd *FleetCommand, active *flags.Command) {
+	switch active.Name {
+	case "check":
+		executeFleetCheck(&cmd.Check)
+	default:
+		fatalf("Unknown command: fleet %s", active.Name)
+	}
+}
+
+func executeFleetCheck(cmd *FleetCheckCommand) {
+	servers := config.expand(cmd.Args.Servers)
+	if len(servers) < 2 {
+		fatal("At least two servers must be provided")
+	}
+	for i, server := range servers {
+		address, _, err := config.server(server, cmd.ConnectionOptions)
+		if err != nil {
+			fatal(err)
+		}
+		servers[i] = address
+	}
+
+	reference, _, err := config.server(cmd.Reference, cmd.ConnectionOptions)
+	if err != nil {
+		fatal(err)
+	}
+
+	members := fleet.Query(servers, fleet.Options{
+		Timeout:     time.Duration(cmd.Timeout) * time.Second,
+		BufferSize:  cmd.Buffer,
+		Dialer:      proxyDialer(cmd.ConnectionOptions),
+		Concurrency: cmd.Parallel,
+	})
+
+	report, err := fleet.Compare(members, reference)
+	if err != nil {
+		fatalf("Failed to check fleet: %s", err)
+	}
+
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(report)
+	} else {
+		printFleetReport(report, formatter)
+	}
+
+	if len(report.Differences) > 0 || len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printFleetReport(report *fleet.Report, formatter *Formatter) {
+	if len(report.Errors) > 0 {
+		formatter.PrintSectionHeader("Errors")
+		t := formatter.NewTable()
+		t.AppendHeader(table.Row{"Server", "Error"})
+
+		addresses := make([]string, 0, len(report.Errors))
+		for address := range report.Errors {
+			addresses = append(addresses, address)
+		}
+		sort.Strings(addresses)
+		for _, address := range addresses {
+			t.AppendRow(table.Row{address, report.Errors[address]})
+		}
+
+		formatter.PrintTable(t)
+	}
+
+	if len(report.Differences) > 0 {
+		formatter.PrintSectionHeader("Differences")
+		t := formatter.NewTable()
+		t.AppendHeader(table.Row{"Field", "Server", "Value", "Expected"})
+		for _, diff := range report.Differences {
+			t.AppendRow(table.Row{diff.Field, diff.Address, orAbsent(diff.Value), orAbsent(diff.Expected)})
+		}
+		formatter.PrintTable(t)
+	}
+
+	if formatter.IsTableFormat() {
+		expected := "majority"
+		if report.Reference != "" {
+			expected = report.Reference
+		}
+		fmt.Printf("Fleet check of %d servers against %s: %d differences, %d errors\n",
+			report.Servers, expected, len(report.Differences), len(report.Errors))
+	}
+}
+
+// orAbsent returns value or "(absent)" placeholder for empty values.
+func orAbsent(value string) string {
+	if value == "" {
+		return "(absent)"
+	}
+
+	return value
+}
@@ -5,25 +5,48 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"text/template"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/woozymasta/a2s/pkg/csvexport"
 )
 
 // Formatter handles output formatting in different formats.
 type Formatter struct {
+	tmpl   *template.Template
 	format string
 }
 
 // NewFormatter creates a new formatter with the specified format.
-func NewFormatter(format string) *Formatter {
+// A template in options switches the format to template.
+func NewFormatter(format string, opts TemplateOptions) *Formatter {
 	normalized := format
 	if normalized == "" {
 		normalized = "table"
 	}
 	normalized = strings.ToLower(normalized)
 
-	return &Formatter{format: normalized}
+	tmpl := parseTemplate(opts)
+	if tmpl != nil {
+		normalized = "template"
+	} else if normalized == "template" {
+		fatal("Template format requires --template or --template-file")
+	}
+
+	return &Formatter{format: normalized, tmpl: tmpl}
+}
+
+// NewTable creates a table writer with the default style,
+// mirrored to stdout only for table and raw formats.
+func (f *Formatter) NewTable() table.Writer {
+	t := table.NewWriter()
+	if f.IsTableFormat() || f.format == "raw" {
+		t.SetOutputMirror(os.Stdout)
+	}
+	t.SetStyle(table.StyleRounded)
+
+	return t
 }
 
 // PrintTable prints data as a table in the specified format.
@@ -39,6 +62,10 @@ func (f *Formatter) PrintTable(t table.Writer) {
 		fmt.Println(t.RenderMarkdown())
 	case "html":
 		fmt.Println(t.RenderHTML())
+	case "csv":
+		fmt.Println(t.RenderCSV())
+	case "tsv":
+		fmt.Println(t.RenderTSV())
 	case "table":
 		fallthrough
 	default:
@@ -46,8 +73,13 @@ func (f *Formatter) PrintTable(t table.Writer) {
 	}
 }
 
-// PrintJSON prints data as JSON.
+// PrintJSON prints data as JSON or executes the template on it for template format.
 func (f *Formatter) PrintJSON(data any) {
+	if f.tmpl != nil {
+		f.PrintTemplate(data)
+		return
+	}
+
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		fatalf("Failed to marshal JSON: %v", err)
@@ -55,6 +87,33 @@ func (f *Formatter) PrintJSON(data any) {
 	fmt.Println(string(jsonData))
 }
 
+// PrintLine prints data as a single JSON Lines object or executes the template on it for template format.
+func (f *Formatter) PrintLine(data any) {
+	if f.tmpl != nil {
+		f.PrintTemplate(data)
+		return
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		fatalf("Failed to marshal JSON: %v", err)
+	}
+	fmt.Println(string(jsonData))
+}
+
+// PrintTemplate executes the template on data, a newline is added if the output does not end with one.
+func (f *Formatter) PrintTemplate(data any) {
+	var out strings.Builder
+	if err := f.tmpl.Execute(&out, data); err != nil {
+		fatalf("Failed to execute template: %v", err)
+	}
+
+	fmt.Print(out.String())
+	if !strings.HasSuffix(out.String(), "\n") {
+		fmt.Println()
+	}
+}
+
 // PrintRaw prints raw data (for rules).
 func (f *Formatter) PrintRaw(data map[string]string) {
 	if f.format == "json" {
@@ -77,9 +136,28 @@ func (f *Formatter) PrintRaw(data map[string]string) {
 	f.PrintTable(t)
 }
 
-// ShouldUseJSON returns true if the format is JSON.
+// PrintDelimited writes data with write for csv and tsv formats and returns false for other formats.
+func (f *Formatter) PrintDelimited(write func(w *csvexport.Writer) error) bool {
+	var w *csvexport.Writer
+	switch f.format {
+	case "csv":
+		w = csvexport.NewWriter(os.Stdout)
+	case "tsv":
+		w = csvexport.NewTSVWriter(os.Stdout)
+	default:
+		return false
+	}
+
+	if err := write(w); err != nil {
+		fatalf("Failed to write %s: %v", f.format, err)
+	}
+
+	return true
+}
+
+// ShouldUseJSON returns true if the format prints data structures: JSON or template.
 func (f *Formatter) ShouldUseJSON() bool {
-	return f.format == "json"
+	return f.format == "json" || f.format == "template"
 }
 
 // GetFormat returns the normalized format string.
@@ -7,29 +7,65 @@ import (
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/csvexport"
 	"github.com/woozymasta/a2s/pkg/keywords"
 	"github.com/woozymasta/steam/utils/appid"
 )
 
 func executeInfo(cmd *InfoCommand) {
-	if cmd.Args.Host == "" {
+	server, single := cmd.Args.server(cmd.BatchOptions)
+	if !single {
+		if cmd.Watch > 0 {
+			fatal("--watch supports a single server")
+		}
+		runBatch(cmd.Args.targets(cmd.BatchOptions), cmd.BatchOptions, cmd.ConnectionOptions, NewFormatter(cmd.Format, cmd.TemplateOptions),
+			table.Row{"Name", "Map", "Players", "Version", "Ping"},
+			func(client *a2s.Client, _ string) (any, []table.Row, error) {
+				info, err := client.GetInfo()
+				if err != nil {
+					return nil, nil, err
+				}
+				return newInfoView(info), []table.Row{infoRow(info)}, nil
+			})
+		return
+	}
+
+	if server.Host == "" {
 		fatal("Host must be provided")
 	}
 
-	client := createClient(cmd.Args.Host, cmd.Args.Port, cmd.Timeout, cmd.Buffer)
+	client := createClient(server, cmd.ConnectionOptions)
 	defer closeClient(client)
 
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	if cmd.Watch > 0 {
+		runWatch(client.Address.String(), cmd.Watch, formatter, stopOnSignal(nil), func() (*watchView, error) {
+			info, err := client.GetInfo()
+			if err != nil {
+				return nil, err
+			}
+			return &watchView{header: table.Row{"Property", "Value"}, rows: infoRows(info), ping: info.Ping}, nil
+		})
+		return
+	}
+
 	info, err := client.GetInfo()
 	if err != nil {
 		fatalf("Failed to get server info: %s", err)
 	}
 
-	formatter := NewFormatter(cmd.Format)
+	printInfo(info, formatter, client.Address.String())
+}
 
+// printInfo prints A2S_INFO response of the server at address.
+func printInfo(info *a2s.Info, formatter *Formatter, address string) {
 	if formatter.ShouldUseJSON() {
 		printInfoJSON(info, formatter)
 		return
 	}
+	if formatter.PrintDelimited(func(w *csvexport.Writer) error { return w.WriteInfo(info) }) {
+		return
+	}
 
 	// Table output
 	t := table.NewWriter()
@@ -39,7 +75,20 @@ func executeInfo(cmd *InfoCommand) {
 	t.SetStyle(table.StyleRounded)
 	t.AppendHeader(table.Row{"Property", "Value"})
 
-	t.AppendRows([]table.Row{
+	t.AppendRows(infoRows(info))
+	t.AppendRow(table.Row{"Server ping:", fmt.Sprintf("%d ms", info.Ping.Milliseconds())})
+
+	formatter.PrintTable(t)
+
+	// Only print footer message for table format
+	if formatter.IsTableFormat() {
+		fmt.Printf("A2S_INFO response for %s\n", address)
+	}
+}
+
+// infoRows returns the property rows of the info table without the ping row.
+func infoRows(info *a2s.Info) []table.Row {
+	rows := []table.Row{
 		{"Query type:", info.Format.String()},
 		{"Protocol:", fmt.Sprintf("%d", info.Protocol)},
 		{"Server name:", info.Name},
@@ -54,41 +103,41 @@ func executeInfo(cmd *InfoCommand) {
 		{"Need password:", fmt.Sprintf("%t", info.Visibility)},
 		{"VAC protected:", fmt.Sprintf("%t", info.VAC)},
 		{"Game version:", info.Version},
-	})
+	}
 
 	// GoldSource specific fields
 	if info.Format == 0x6D {
 		if info.Address != "" {
-			t.AppendRow(table.Row{"Server address:", info.Address})
+			rows = append(rows, table.Row{"Server address:", info.Address})
 		}
 
 		if info.Mod != nil {
-			t.AppendRows([]table.Row{
+			rows = append(rows, []table.Row{
 				{"Mod URL:", info.Mod.Link},
 				{"Download URL:", info.Mod.DownloadLink},
 				{"Mod Version:", fmt.Sprintf("%d", info.Mod.Version)},
 				{"Mod Size:", fmt.Sprintf("%d", info.Mod.Size)},
 				{"Multiplayer only:", fmt.Sprintf("%t", info.Mod.Type)},
 				{"Custom DLL:", fmt.Sprintf("%t", info.Mod.DLL)},
-			})
+			}...)
 		}
 	}
 
 	// EDF fields
 	if info.EDF != 0 {
 		if info.Port != 0 {
-			t.AppendRow(table.Row{"Port:", fmt.Sprintf("%d", info.Port)})
+			rows = append(rows, table.Row{"Port:", fmt.Sprintf("%d", info.Port)})
 		}
 
 		if info.SteamID != 0 {
-			t.AppendRow(table.Row{"Server SteamID:", fmt.Sprintf("%d", info.SteamID)})
+			rows = append(rows, table.Row{"Server SteamID:", fmt.Sprintf("%d", info.SteamID)})
 		}
 
 		if (info.EDF & 0x40) != 0 {
-			t.AppendRows([]table.Row{
+			rows = append(rows, []table.Row{
 				{"SourceTV Port:", fmt.Sprintf("%d", info.SourceTVPort)},
 				{"SourceTV Name:", info.SourceTVName},
-			})
+			}...)
 		}
 
 		if len(info.Keywords) > 0 {
@@ -96,7 +145,7 @@ func executeInfo(cmd *InfoCommand) {
 			switch info.ID {
 			case appid.Arma3.Uint64():
 				arma := keywords.ParseArma3(info.Keywords)
-				t.AppendRows([]table.Row{
+				rows = append(rows, []table.Row{
 					{"Type of game:", arma.GameType.String()},
 					{"Server OS:", arma.Platform.String()},
 					{"Content hash:", arma.LoadedContentHash},
@@ -116,11 +165,11 @@ func executeInfo(cmd *InfoCommand) {
 					{"Verify signatures:", fmt.Sprintf("%t", arma.VerifySignatures)},
 					{"Dedicated:", fmt.Sprintf("%t", arma.Dedicated)},
 					{"Enabled file patching:", fmt.Sprintf("%t", arma.AllowedFilePatching)},
-				})
+				}...)
 
 			case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
 				dayz := keywords.ParseDayZ(info.Keywords)
-				t.AppendRows([]table.Row{
+				rows = append(rows, []table.Row{
 					{"Shard:", dayz.Shard},
 					{"In game time:", dayz.Time.String()},
 					{"Time day x:", fmt.Sprintf("%f", dayz.TimeDayAccel)},
@@ -135,47 +184,68 @@ func executeInfo(cmd *InfoCommand) {
 					{"Whitelist:", fmt.Sprintf("%t", dayz.Whitelist)},
 					{"File patching:", fmt.Sprintf("%t", dayz.FlePatching)},
 					{"Need DLC:", fmt.Sprintf("%t", dayz.DLC)},
-				})
+				}...)
 			}
 		}
 	}
 
-	t.AppendRow(table.Row{"Server ping:", fmt.Sprintf("%d ms", info.Ping.Milliseconds())})
+	return rows
+}
 
-	formatter.PrintTable(t)
+func printInfoJSON(info *a2s.Info, formatter *Formatter) {
+	formatter.PrintJSON(newInfoView(info))
+}
 
-	// Only print footer message for table format
-	if cmd.Format == "table" || cmd.Format == "" {
-		fmt.Printf("A2S_INFO response for %s\n", client.Address)
-	}
+// infoView is info with parsed Arma 3 and DayZ keywords, as printed in JSON and templates.
+type infoView struct {
+	*a2s.Info
+	Keywords any `json:"keywords,omitempty"` // *keywords.Arma3, *keywords.DayZ or raw keywords of other games
 }
 
-func printInfoJSON(info *a2s.Info, formatter *Formatter) {
-	// Create a map to hold the JSON structure
-	jsonMap := make(map[string]any)
+// newInfoView parses keywords of info for Arma 3 and DayZ.
+func newInfoView(info *a2s.Info) infoView {
+	view := infoView{Info: info, Keywords: info.Keywords}
+
+	switch info.ID {
+	case appid.Arma3.Uint64():
+		view.Keywords = keywords.ParseArma3(info.Keywords)
+	case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
+		view.Keywords = keywords.ParseDayZ(info.Keywords)
+	}
+
+	return view
+}
 
+// MarshalJSON encodes info with parsed keywords, raw keywords of other games are omitted.
+func (v infoView) MarshalJSON() ([]byte, error) {
 	// Marshal info to JSON first
-	jsonData, err := json.Marshal(info)
+	jsonData, err := json.Marshal(v.Info)
 	if err != nil {
-		fatalf("Failed to marshal Info: %v", err)
+		return nil, err
 	}
 
-	// Unmarshal into a map to add custom fields
+	// Unmarshal into a map to replace keywords
+	jsonMap := make(map[string]any)
 	if err := json.Unmarshal(jsonData, &jsonMap); err != nil {
-		fatalf("Failed to unmarshal JSON: %v", err)
+		return nil, err
 	}
 
-	// Parse keywords for Arma3/DayZ
 	delete(jsonMap, "keywords")
-
-	switch info.ID {
-	case appid.Arma3.Uint64():
-		armaData := keywords.ParseArma3(info.Keywords)
-		jsonMap["keywords"] = armaData
-	case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
-		dayZData := keywords.ParseDayZ(info.Keywords)
-		jsonMap["keywords"] = dayZData
+	switch v.Keywords.(type) {
+	case *keywords.Arma3, *keywords.DayZ:
+		jsonMap["keywords"] = v.Keywords
 	}
 
-	formatter.PrintJSON(jsonMap)
+	return json.Marshal(jsonMap)
+}
+
+// infoRow returns the summary row of info for merged tables: name, map, players, version and ping.
+func infoRow(info *a2s.Info) table.Row {
+	return table.Row{
+		info.Name,
+		info.Map,
+		fmt.Sprintf("%d/%d", info.Players, info.MaxPlayers),
+		info.Version,
+		fmt.Sprintf("%d ms", info.Ping.Milliseconds()),
+	}
 }
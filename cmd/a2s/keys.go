@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jessevdk/go-flags"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+)
+
+func executeKeys(cmd *KeysCommand, active *flags.Command) {
+	switch active.Name {
+	case "check":
+		executeKeysCheck(&cmd.Check)
+	default:
+		fatalf("Unknown command: keys %s", active.Name)
+	}
+}
+
+func executeKeysCheck(cmd *KeysCheckCommand) {
+	if cmd.Args.Host == "" {
+		fatal("Host must be provided")
+	}
+
+	keys, err := a3sb.ScanKeys(cmd.KeysDir)
+	if err != nil {
+		fatalf("Failed to read keys directory: %s", err)
+	}
+
+	client := createClient(cmd.Args, cmd.ConnectionOptions)
+	defer closeClient(client)
+
+	rules, _ := getA3SBRules(client, config.game(cmd.Args.Host, cmd.Game))
+	report := rules.CompareSignatures(keys)
+
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(struct {
+			*a3sb.SignatureReport
+			OK bool `json:"ok"`
+		}{report, report.OK()})
+	} else {
+		t := formatter.NewTable()
+		t.AppendHeader(table.Row{"Key", "Status", "Path"})
+		for _, name := range report.Present {
+			t.AppendRow(table.Row{name, "present", ""})
+		}
+		for _, name := range report.Missing {
+			t.AppendRow(table.Row{name, "missing", ""})
+		}
+		for _, key := range report.Unexpected {
+			t.AppendRow(table.Row{key.Name, "unexpected", key.Path})
+		}
+		formatter.PrintTable(t)
+
+		if formatter.IsTableFormat() {
+			fmt.Printf("Signatures check for %s: %d present, %d missing, %d unexpected\n",
+				client.Address, len(report.Present), len(report.Missing), len(report.Unexpected))
+		}
+	}
+
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
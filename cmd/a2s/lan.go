@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"net/netip"
+	"os"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/discovery"
+)
+
+func executeLAN(cmd *LANCommand) {
+	ports, err := discovery.ParsePorts(cmd.Ports)
+	if err != nil {
+		fatalf("Failed to parse ports: %s", err)
+	}
+
+	opts := discovery.LANOptions{
+		Interfaces: cmd.Interfaces,
+		Ports:      ports,
+		Window:     time.Duration(cmd.Window) * time.Millisecond,
+	}
+
+	for _, value := range cmd.Addresses {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			fatalf("Failed to parse address: %s", err)
+		}
+		opts.Addresses = append(opts.Addresses, addr)
+	}
+
+	if cmd.Format == "jsonl" {
+		encoder := json.NewEncoder(os.Stdout)
+		opts.OnFound = func(result discovery.Result) {
+			if err := encoder.Encode(result); err != nil {
+				fatalf("Failed to write result: %s", err)
+			}
+		}
+	}
+
+	results, err := discovery.DiscoverLAN(opts)
+	if err != nil {
+		fatalf("Failed to discover LAN servers: %s", err)
+	}
+
+	if cmd.Format == "jsonl" {
+		return
+	}
+
+	printDiscovered(results, NewFormatter(cmd.Format, cmd.TemplateOptions))
+}
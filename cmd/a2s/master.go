@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/filter"
+	"github.com/woozymasta/a2s/pkg/webapi"
+)
+
+func executeMaster(cmd *MasterCommand) {
+	client := &webapi.Client{
+		HTTPClient: &http.Client{Timeout: time.Duration(cmd.Timeout) * time.Second},
+		Key:        cmd.Key,
+		Limit:      cmd.Limit,
+	}
+
+	servers, err := client.GetServerList(cmd.Args.Filter)
+	if err != nil {
+		fatalf("Failed to get server list: %s", err)
+	}
+
+	match := compileFilter(cmd.Filter)
+	servers = slices.DeleteFunc(servers, func(server webapi.Server) bool {
+		return !match(filter.Server{Info: masterInfo(server)})
+	})
+
+	if cmd.Addresses {
+		for _, server := range servers {
+			fmt.Println(server.Addr)
+		}
+		return
+	}
+
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(servers)
+		return
+	}
+
+	t := formatter.NewTable()
+	t.AppendHeader(table.Row{"#", "Address", "Name", "Map", "Players", "Version", "AppID"})
+	for i, server := range servers {
+		t.AppendRow(table.Row{
+			fmt.Sprintf("%d", i+1),
+			server.Addr,
+			server.Name,
+			server.Map,
+			fmt.Sprintf("%d/%d", server.Players, server.MaxPlayers),
+			server.Version,
+			fmt.Sprintf("%d", server.AppID),
+		})
+	}
+	formatter.PrintTable(t)
+
+	if formatter.IsTableFormat() {
+		fmt.Printf("%d servers from Steam Web API\n", len(servers))
+	}
+}
+
+// masterInfo converts a server list entry to info for --filter, fields absent in the list are zero.
+func masterInfo(server webapi.Server) *a2s.Info {
+	info := &a2s.Info{
+		Name:       server.Name,
+		Map:        server.Map,
+		Folder:     server.GameDir,
+		Game:       server.Product,
+		Version:    server.Version,
+		ID:         server.AppID,
+		SteamID:    server.SteamID,
+		Port:       server.GamePort,
+		Players:    byte(min(server.Players, 255)),
+		MaxPlayers: byte(min(server.MaxPlayers, 255)),
+		Bots:       byte(min(server.Bots, 255)),
+		ServerType: 'l',
+		VAC:        server.Secure,
+	}
+
+	if server.Dedicated {
+		info.ServerType = 'd'
+	}
+	if server.OS != "" {
+		info.Environment = a2s.Environment(server.OS[0])
+	}
+	if server.GameType != "" {
+		info.Keywords = strings.Split(server.GameType, ",")
+	}
+
+	return info
+}
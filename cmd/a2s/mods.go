@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jessevdk/go-flags"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+)
+
+func executeMods(cmd *ModsCommand, active *flags.Command) {
+	switch active.Name {
+	case "export":
+		executeModsExport(&cmd.Export)
+	case "check":
+		executeModsCheck(&cmd.Check)
+	default:
+		fatalf("Unknown command: mods %s", active.Name)
+	}
+}
+
+func executeModsExport(cmd *ModsExportCommand) {
+	if cmd.Args.Host == "" {
+		fatal("Host must be provided")
+	}
+
+	client := createClient(cmd.Args, cmd.ConnectionOptions)
+	defer closeClient(client)
+
+	rules, info := getA3SBRules(client, config.game(cmd.Args.Host, cmd.Game))
+
+	out := io.Writer(os.Stdout)
+	if cmd.Output != "" {
+		file, err := os.Create(cmd.Output)
+		if err != nil {
+			fatalf("Failed to create output file: %s", err)
+		}
+		defer func() {
+			if err := file.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to close output file: %s\n", err)
+			}
+		}()
+		out = file
+	}
+
+	w := bufio.NewWriter(out)
+
+	switch cmd.Format {
+	case "launch":
+		param, err := rules.LaunchParameter()
+		if err != nil {
+			fatalf("Failed to export launch parameter: %s", err)
+		}
+		fmt.Fprintln(w, param)
+
+	case "ids":
+		for _, id := range rules.WorkshopIDs() {
+			fmt.Fprintln(w, id)
+		}
+
+	default:
+		name := cmd.Name
+		if name == "" {
+			if info == nil {
+				info, _ = client.GetInfo()
+			}
+			if info != nil {
+				name = info.Name
+			} else {
+				name = client.Address.String()
+			}
+		}
+
+		if err := rules.WritePreset(w, name); err != nil {
+			fatalf("Failed to write preset: %s", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		fatalf("Failed to write export: %s", err)
+	}
+}
+
+func executeModsCheck(cmd *ModsCheckCommand) {
+	if cmd.Args.Host == "" {
+		fatal("Host must be provided")
+	}
+
+	var version *a3sb.GameVersion
+	if cmd.GameVersion != "" {
+		v, err := a3sb.ParseGameVersion(cmd.GameVersion)
+		if err != nil {
+			fatalf("Failed to parse game version: %s", err)
+		}
+		version = &v
+	}
+
+	local, err := a3sb.ScanWorkshop(cmd.WorkshopDir)
+	if err != nil {
+		if !errors.Is(err, a3sb.ErrWorkshopMeta) {
+			fatalf("Failed to scan workshop directory: %s", err)
+		}
+		// Mods with malformed meta.cpp are skipped, the others are still compared
+		fmt.Fprintf(os.Stderr, "Skipped local mods:\n%s\n", err)
+	}
+
+	client := createClient(cmd.Args, cmd.ConnectionOptions)
+	defer closeClient(client)
+
+	rules, _ := getA3SBRules(client, config.game(cmd.Args.Host, cmd.Game))
+	report := rules.CompareMods(local, version)
+
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(struct {
+			*a3sb.ModReport
+			CanJoin bool `json:"can_join"`
+		}{report, report.CanJoin()})
+	} else {
+		printModReport(report, len(rules.Mods), cmd.ShowExtra, formatter)
+		if formatter.IsTableFormat() {
+			fmt.Printf("Mods check for %s\n", client.Address)
+		}
+	}
+
+	if !report.CanJoin() {
+		os.Exit(1)
+	}
+}
+
+func printModReport(report *a3sb.ModReport, required int, showExtra bool, formatter *Formatter) {
+	formatter.PrintSectionHeader("Summary")
+	t := formatter.NewTable()
+	t.AppendHeader(table.Row{"Check", "Value"})
+	t.AppendRows([]table.Row{
+		{"Server mods:", fmt.Sprintf("%d", required)},
+		{"Installed:", fmt.Sprintf("%d", len(report.Matched))},
+		{"Missing:", fmt.Sprintf("%d", len(report.Missing))},
+		{"Outdated:", fmt.Sprintf("%d", len(report.Outdated))},
+		{"Extra:", fmt.Sprintf("%d", len(report.Extra))},
+	})
+	if report.Version != nil {
+		t.AppendRow(table.Row{"Required version:", fmt.Sprintf("%d (local %d, ok %t)", report.RequiredVersion, report.Version.Version, report.VersionOK)})
+		if report.Version.Build != 0 {
+			t.AppendRow(table.Row{"Required build:", fmt.Sprintf("%d (local %d, ok %t)", report.RequiredBuild, report.Version.Build, report.BuildOK)})
+		} else {
+			t.AppendRow(table.Row{"Required build:", fmt.Sprintf("%d (local unknown, not checked)", report.RequiredBuild)})
+		}
+	}
+	t.AppendRow(table.Row{"Can join:", fmt.Sprintf("%t", report.CanJoin())})
+	formatter.PrintTable(t)
+
+	if len(report.Missing) > 0 {
+		formatter.PrintSectionHeader("Missing Mods")
+		t := formatter.NewTable()
+		t.AppendHeader(table.Row{"#", "Mod Name", "Mod URL"})
+		for i, mod := range report.Missing {
+			t.AppendRow(table.Row{fmt.Sprintf("%d", i+1), mod.Name, a3sb.WorkshopURL + strconv.FormatUint(mod.ID, 10)})
+		}
+		formatter.PrintTable(t)
+	}
+
+	printLocalMods("Outdated Mods", report.Outdated, formatter)
+	if showExtra {
+		printLocalMods("Extra Mods", report.Extra, formatter)
+	}
+}
+
+func printLocalMods(title string, mods []a3sb.LocalMod, formatter *Formatter) {
+	if len(mods) == 0 {
+		return
+	}
+
+	formatter.PrintSectionHeader(title)
+	t := formatter.NewTable()
+	t.AppendHeader(table.Row{"#", "Mod Name", "Workshop ID", "Path"})
+	for i, mod := range mods {
+		t.AppendRow(table.Row{fmt.Sprintf("%d", i+1), mod.Name, fmt.Sprintf("%d", mod.ID), mod.Path})
+	}
+	formatter.PrintTable(t)
+}
+
+// getA3SBRules queries A3SB rules, detecting the game via A2S_INFO when not specified.
+// Returns the server info if it was queried for detection.
+func getA3SBRules(client *a2s.Client, game string) (*a3sb.Rules, *a2s.Info) {
+	var info *a2s.Info
+
+	appID := gameToAppID(game)
+	if game != "" && appID == 0 {
+		fatalf("Unknown game: %s. Supported games: arma3, dayz", game)
+	}
+
+	if appID == 0 {
+		var err error
+		if info, err = client.GetInfo(); err != nil {
+			fatalf("Failed to get server info: %s", err)
+		}
+		appID = info.ID
+	}
+
+	if !isA3SBGame(appID) {
+		fatalf("Server AppID %d is not Arma 3 or DayZ, A3SB rules are not available", appID)
+	}
+
+	a3sbClient := &a3sb.Client{Client: client}
+	rules, err := a3sbClient.GetRules(appID)
+	if err != nil {
+		fatalf("Failed to get server rules: %s", err)
+	}
+
+	return rules, info
+}
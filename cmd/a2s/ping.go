@@ -1,16 +1,317 @@
 package main
 
 import (
-	"github.com/woozymasta/a2s/internal/ping"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/ping"
+	"golang.org/x/term"
 )
 
+// pingSummary is the ping statistics of a server as printed in JSON and templates.
+type pingSummary struct {
+	Address string `json:"address"`
+	ping.Stats
+}
+
 func executePing(cmd *PingCommand) {
-	if cmd.Args.Host == "" {
+	if cmd.PingPeriod <= 0 {
+		fatal("Ping period must be positive")
+	}
+
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	server, single := cmd.Args.server(BatchOptions{})
+	if !single {
+		runPingTargets(cmd.Args.targets(BatchOptions{}), cmd, formatter)
+		return
+	}
+
+	if server.Host == "" {
 		fatal("Host must be provided")
 	}
 
-	client := createClient(cmd.Args.Host, cmd.Args.Port, cmd.Timeout, cmd.Buffer)
+	client := createClient(server, cmd.ConnectionOptions)
 	defer closeClient(client)
 
-	ping.Start(client, cmd.PingCount, cmd.PingPeriod)
+	address := client.Address.String()
+	period := seconds(cmd.PingPeriod)
+
+	// Probes are printed only with the table format, other formats print the summary
+	verbose := formatter.IsTableFormat()
+	if verbose {
+		if cmd.PingCount != 0 {
+			fmt.Printf("Start %d times ping %s with %s period\n\n", cmd.PingCount, address, period)
+		} else {
+			fmt.Printf("Start infinity ping %s with %s period\n\n", address, period)
+		}
+	}
+
+	stop := stopOnSignal(func() {
+		if verbose {
+			fmt.Println("Received signal, stopping...")
+		}
+	})
+
+	stats := ping.Run(client, ping.Options{
+		Count:   cmd.PingCount,
+		Period:  period,
+		Timeout: seconds(cmd.PingTimeout),
+		Stop:    stop,
+		OnResult: func(result ping.Result) {
+			switch {
+			case result.Err != nil:
+				fmt.Fprintf(os.Stderr, "Failed to get ping seq=%d: %v\n", result.Seq, result.Err)
+			case verbose:
+				fmt.Printf("A2S_INFO response server=%s seq=%d folder=%q name=%q time=%s\n",
+					address, result.Seq, result.Info.Folder, result.Info.Name, result.Info.Ping)
+			}
+		},
+	})
+
+	printPingStats(pingSummary{Address: address, Stats: stats}, formatter)
+
+	if stats.Received == 0 && stats.Sent > 0 {
+		os.Exit(1)
+	}
+}
+
+// printPingStats prints the ping summary.
+func printPingStats(summary pingSummary, formatter *Formatter) {
+	stats := summary.Stats
+
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(summary)
+		return
+	}
+
+	if formatter.IsTableFormat() {
+		fmt.Printf("\nTransmitted %d requests, received %d responses, lost %d (%.1f%% loss)\n", stats.Sent, stats.Received, stats.Lost, stats.Loss)
+		if stats.Samples < stats.Received {
+			fmt.Printf("Percentiles of the latest %d responses\n", stats.Samples)
+		}
+		fmt.Printf("Min=%s Avg=%s Max=%s StdDev=%s Jitter=%s\n", stats.Min, stats.Avg, stats.Max, stats.StdDev, stats.Jitter)
+		fmt.Printf("P50=%s P90=%s P99=%s\n", stats.P50, stats.P90, stats.P99)
+		return
+	}
+
+	t := formatter.NewTable()
+	t.AppendHeader(table.Row{"Property", "Value"})
+	t.AppendRows([]table.Row{
+		{"Address", summary.Address},
+		{"Sent", stats.Sent},
+		{"Received", stats.Received},
+		{"Lost", stats.Lost},
+		{"Loss %", fmt.Sprintf("%.1f", stats.Loss)},
+		{"Min", stats.Min.String()},
+		{"Avg", stats.Avg.String()},
+		{"Max", stats.Max.String()},
+		{"StdDev", stats.StdDev.String()},
+		{"Jitter", stats.Jitter.String()},
+		{"P50", stats.P50.String()},
+		{"P90", stats.P90.String()},
+		{"P99", stats.P99.String()},
+	})
+	formatter.PrintTable(t)
+}
+
+// pingTarget is a server of the multi-target ping with its ring buffer.
+type pingTarget struct {
+	buffer  *ping.Buffer
+	address string
+	name    string
+}
+
+// pingUpdate is the server name received by a probe of the target.
+type pingUpdate struct {
+	name   string
+	target int
+}
+
+// pingTargetsSummary is the multi-target ping statistics as printed in JSON and templates.
+type pingTargetsSummary struct {
+	Targets []pingSummary `json:"targets"`
+	Total   ping.Stats    `json:"total"`
+}
+
+// runPingTargets pings all targets concurrently with a ring buffer per target. The table format
+// on a terminal redraws an mtr-style table every period, the final table ends with a total row
+// over all targets. Exits with code 1 if any target did not respond.
+func runPingTargets(addresses []string, cmd *PingCommand, formatter *Formatter) {
+	period := seconds(cmd.PingPeriod)
+	live := formatter.IsTableFormat() && term.IsTerminal(int(os.Stdout.Fd()))
+
+	targets := make([]*pingTarget, len(addresses))
+	clients := make([]*a2s.Client, len(addresses))
+	for i, target := range addresses {
+		address, conn, err := config.server(target, cmd.ConnectionOptions)
+		if err == nil {
+			address, err = queryAddress(address, conn)
+		}
+		if err == nil {
+			clients[i], err = dialClient(address, conn)
+		}
+		if err != nil {
+			fatalf("Failed to create client for %s: %s", target, err)
+		}
+		defer closeClient(clients[i])
+
+		targets[i] = &pingTarget{address: address, buffer: ping.NewBuffer(0)}
+	}
+
+	total := ping.NewBuffer(0)
+	updates := make(chan pingUpdate)
+	done := make(chan struct{})
+	stop := stopOnSignal(nil)
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ping.Run(clients[i], ping.Options{
+				Buffer:  target.buffer,
+				Count:   cmd.PingCount,
+				Period:  period,
+				Timeout: seconds(cmd.PingTimeout),
+				Stop:    stop,
+				OnResult: func(result ping.Result) {
+					if result.Err != nil {
+						total.AddLoss()
+						return
+					}
+					total.Add(result.Info.Ping)
+					updates <- pingUpdate{target: i, name: result.Info.Name}
+				},
+			})
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	start := time.Now()
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	draw := func() {
+		// Clear the screen and move the cursor home
+		fmt.Print("\x1b[H\x1b[2J")
+		fmt.Printf("Every %s: %d targets  up %s\n", period, len(targets), time.Since(start).Round(time.Second))
+		formatter.PrintTable(pingTargetsTable(targets, nil, formatter))
+	}
+
+loop:
+	for {
+		select {
+		case update := <-updates:
+			targets[update.target].name = update.name
+		case <-ticker.C:
+			if live {
+				draw()
+			}
+		case <-done:
+			break loop
+		}
+	}
+
+	summary := pingTargetsSummary{Total: ping.CalculateStats(total)}
+	failed := false
+	for _, target := range targets {
+		stats := ping.CalculateStats(target.buffer)
+		summary.Targets = append(summary.Targets, pingSummary{Address: target.address, Stats: stats})
+		failed = failed || (stats.Received == 0 && stats.Sent > 0)
+	}
+
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(summary)
+	} else {
+		if live {
+			fmt.Print("\x1b[H\x1b[2J")
+		}
+		formatter.PrintTable(pingTargetsTable(targets, &summary.Total, formatter))
+
+		if formatter.IsTableFormat() {
+			stats := summary.Total
+			fmt.Printf("Transmitted %d requests, received %d responses, lost %d (%.1f%% loss)\n", stats.Sent, stats.Received, stats.Lost, stats.Loss)
+			fmt.Printf("P50=%s P90=%s P99=%s\n", stats.P50, stats.P90, stats.P99)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// pingTargetsTable returns the mtr-style table of targets with an optional total row.
+func pingTargetsTable(targets []*pingTarget, total *ping.Stats, formatter *Formatter) table.Writer {
+	t := formatter.NewTable()
+	t.AppendHeader(table.Row{"#", "Address", "Name", "Loss %", "Sent", "Last", "Avg", "Best", "Worst", "StdDev", "Jitter"})
+
+	for i, target := range targets {
+		stats := ping.CalculateStats(target.buffer)
+		t.AppendRow(append(table.Row{i + 1, target.address, text.Trim(colorCodes.ReplaceAllString(target.name, ""), 32)},
+			pingRow(stats, stats.Last, stats.Jitter)...))
+	}
+
+	if total != nil {
+		t.AppendSeparator()
+		t.AppendRow(append(table.Row{"", "Total", ""}, pingRow(*total, -1, -1)...))
+	}
+
+	return t
+}
+
+// pingRow returns the loss, sent and round-trip time cells of statistics,
+// last and jitter are left empty if negative.
+func pingRow(stats ping.Stats, last, jitter time.Duration) table.Row {
+	ms := func(d time.Duration) string {
+		switch {
+		case d < 0:
+			return ""
+		case stats.Received == 0:
+			return "-"
+		}
+		return fmt.Sprintf("%.1f ms", float64(d)/float64(time.Millisecond))
+	}
+
+	return table.Row{
+		fmt.Sprintf("%.1f", stats.Loss),
+		stats.Sent,
+		ms(last),
+		ms(stats.Avg),
+		ms(stats.Min),
+		ms(stats.Max),
+		ms(stats.StdDev),
+		ms(jitter),
+	}
+}
+
+// stopOnSignal returns a channel closed on SIGINT or SIGTERM after calling the optional callback.
+func stopOnSignal(callback func()) <-chan struct{} {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+
+	stop := make(chan struct{})
+	go func() {
+		<-signalChan
+		if callback != nil {
+			callback()
+		}
+		close(stop)
+	}()
+
+	return stop
+}
+
+// seconds converts fractional seconds of an option to a duration.
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/woozymasta/a2s/pkg/ping"
+)
+
+func TestPingRow(t *testing.T) {
+	buffer := ping.NewBuffer(0)
+	buffer.Add(10 * time.Millisecond)
+	buffer.Add(30 * time.Millisecond)
+	buffer.AddLoss()
+	stats := ping.CalculateStats(buffer)
+
+	want := table.Row{"33.3", 3, "30.0 ms", "20.0 ms", "10.0 ms", "30.0 ms", "10.0 ms", "20.0 ms"}
+	if got := pingRow(stats, stats.Last, stats.Jitter); !slices.Equal(got, want) {
+		t.Errorf("pingRow() = %v, want %v", got, want)
+	}
+
+	lost := ping.NewBuffer(0)
+	lost.AddLoss()
+	want = table.Row{"100.0", 1, "", "-", "-", "-", "-", ""}
+	if got := pingRow(ping.CalculateStats(lost), -1, -1); !slices.Equal(got, want) {
+		t.Errorf("pingRow() of lost target = %v, want %v", got, want)
+	}
+}
+
+func TestPingTargetsTable(t *testing.T) {
+	targets := []*pingTarget{
+		{address: "203.0.113.10:27016", name: "^1DayZ EU", buffer: ping.NewBuffer(0)},
+		{address: "203.0.113.10:27017", buffer: ping.NewBuffer(0)},
+	}
+	targets[0].buffer.Add(20 * time.Millisecond)
+	targets[1].buffer.AddLoss()
+
+	total := ping.Stats{Sent: 2, Received: 1, Lost: 1, Loss: 50, Avg: 20 * time.Millisecond}
+	out := pingTargetsTable(targets, &total, NewFormatter("md", TemplateOptions{})).RenderMarkdown()
+
+	lines := strings.Split(out, "\n")
+	if len(lines) != 5 {
+		t.Fatalf("table lines = %d:\n%s", len(lines), out)
+	}
+	for i, want := range []string{"| 1 | 203.0.113.10:27016 | DayZ EU | 0.0 | 1 | 20.0 ms |", "| 2 | 203.0.113.10:27017 |  | 100.0 | 1 | - |", "|  | Total |  | 50.0 | 2 |  | 20.0 ms |"} {
+		if !strings.HasPrefix(lines[i+2], want) {
+			t.Errorf("row %d = %q, want prefix %q", i+1, lines[i+2], want)
+		}
+	}
+}
+
+func TestPingTargets(t *testing.T) {
+	if _, single := (TargetArgs{Host: "203.0.113.10", Port: "27016"}).server(BatchOptions{}); !single {
+		t.Error("host and port are not a single target")
+	}
+
+	args := TargetArgs{Host: "203.0.113.10:27016", Port: "203.0.113.10:27017"}
+	if _, single := args.server(BatchOptions{}); single {
+		t.Error("two query ports are a single target")
+	}
+	if got := args.targets(BatchOptions{}); !slices.Equal(got, []string{"203.0.113.10:27016", "203.0.113.10:27017"}) {
+		t.Errorf("targets() = %v", got)
+	}
+}
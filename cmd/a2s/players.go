@@ -3,38 +3,115 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/csvexport"
 )
 
 func executePlayers(cmd *PlayersCommand) {
-	if cmd.Args.Host == "" {
+	server, single := cmd.Args.server(cmd.BatchOptions)
+	if !single {
+		if cmd.Watch > 0 {
+			fatal("--watch supports a single server")
+		}
+		runBatch(cmd.Args.targets(cmd.BatchOptions), cmd.BatchOptions, cmd.ConnectionOptions, NewFormatter(cmd.Format, cmd.TemplateOptions),
+			table.Row{"Name", "Score", "PlayTime"},
+			func(client *a2s.Client, _ string) (any, []table.Row, error) {
+				players, err := client.GetPlayers()
+				if err != nil {
+					return nil, nil, err
+				}
+
+				rows := make([]table.Row, 0, len(*players))
+				for _, player := range *players {
+					rows = append(rows, table.Row{player.Name, player.Score, player.Duration.String()})
+				}
+				return *players, rows, nil
+			})
+		return
+	}
+
+	if server.Host == "" {
 		fatal("Host must be provided")
 	}
 
-	client := createClient(cmd.Args.Host, cmd.Args.Port, cmd.Timeout, cmd.Buffer)
+	client := createClient(server, cmd.ConnectionOptions)
 	defer closeClient(client)
 
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	if cmd.Watch > 0 {
+		runWatch(client.Address.String(), cmd.Watch, formatter, stopOnSignal(nil), func() (*watchView, error) {
+			// Pipelined request reports the round trip time of the response
+			responses, err := client.GetPipelined(a2s.PlayerRequest)
+			if err != nil {
+				return nil, err
+			}
+			players, err := responses[0].Players()
+			if err != nil {
+				return nil, err
+			}
+
+			// Players are identified by name, their number and play time change on every refresh
+			header, rows := playerTable(players)
+			return &watchView{
+				header:   header,
+				rows:     rows,
+				key:      slices.Index(header, any("Name")),
+				volatile: []int{0, slices.Index(header, any("PlayTime"))},
+				ping:     responses[0].Ping,
+			}, nil
+		})
+		return
+	}
+
 	players, err := client.GetPlayers()
 	if err != nil {
 		fatalf("Failed to get players: %s", err)
 	}
 
-	formatter := NewFormatter(cmd.Format)
+	printPlayers(*players, formatter, client.Address.String())
+}
 
+// printPlayers prints A2S_PLAYER response of the server at address.
+func printPlayers(players []a2s.Player, formatter *Formatter, address string) {
 	if formatter.ShouldUseJSON() {
 		formatter.PrintJSON(players)
 		return
 	}
+	if formatter.PrintDelimited(func(w *csvexport.Writer) error { return w.WritePlayers(players) }) {
+		return
+	}
 
-	if len(*players) == 0 {
+	if len(players) == 0 {
 		fmt.Println("The server is empty and there are no players to print ...")
 		return
 	}
 
+	header, rows := playerTable(players)
+
+	t := table.NewWriter()
+	if formatter.IsTableFormat() {
+		t.SetOutputMirror(os.Stdout)
+	}
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(header)
+	t.AppendRows(rows)
+
+	formatter.PrintTable(t)
+
+	// Only print footer message for table format
+	if formatter.IsTableFormat() {
+		fmt.Printf("A2S_PLAYERS response for %s\n", address)
+	}
+}
+
+// playerTable returns the header and rows of the players table, columns without values are omitted.
+func playerTable(players []a2s.Player) (table.Row, []table.Row) {
 	// Determine which columns to show
 	counter := [4]byte{}
-	for _, player := range *players {
+	for _, player := range players {
 		if player.Duration != 0 {
 			counter[0]++
 		}
@@ -63,14 +140,9 @@ func executePlayers(cmd *PlayersCommand) {
 		columns = append(columns, "Index")
 	}
 
-	t := table.NewWriter()
-	if formatter.IsTableFormat() {
-		t.SetOutputMirror(os.Stdout)
-	}
-	t.SetStyle(table.StyleRounded)
-	t.AppendHeader(table.Row(columns))
+	rows := make([]table.Row, 0, len(players))
 
-	for i, player := range *players {
+	for i, player := range players {
 		row := []interface{}{fmt.Sprintf("%d", i+1)}
 
 		if counter[0] > 0 {
@@ -86,13 +158,8 @@ func executePlayers(cmd *PlayersCommand) {
 			row = append(row, fmt.Sprint(player.Index))
 		}
 
-		t.AppendRow(table.Row(row))
+		rows = append(rows, table.Row(row))
 	}
 
-	formatter.PrintTable(t)
-
-	// Only print footer message for table format
-	if formatter.IsTableFormat() {
-		fmt.Printf("A2S_PLAYERS response for %s\n", client.Address)
-	}
+	return table.Row(columns), rows
 }
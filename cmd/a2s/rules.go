@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/woozymasta/a2s/internal/flatten"
 	"github.com/woozymasta/a2s/pkg/a2s"
 	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/csvexport"
 	"github.com/woozymasta/steam/utils/appid"
 )
 
@@ -25,33 +28,89 @@ func gameToAppID(game string) uint64 {
 	}
 }
 
+// gameAppID returns the AppID of a game name, an error if the game is not supported.
+func gameAppID(game string) (uint64, error) {
+	id := gameToAppID(game)
+	if id == 0 {
+		return 0, fmt.Errorf("Unknown game: %s. Supported games: arma3, dayz", game)
+	}
+
+	return id, nil
+}
+
 // isA3SBGame checks if the given AppID corresponds to Arma3 or DayZ.
 func isA3SBGame(id uint64) bool {
 	return id == appid.Arma3.Uint64() || id == appid.DayZ.Uint64() || id == appid.DayZExp.Uint64()
 }
 
 func executeRules(cmd *RulesCommand) {
-	if cmd.Args.Host == "" {
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+
+	server, single := cmd.Args.server(cmd.BatchOptions)
+	if !single {
+		if cmd.Watch > 0 {
+			fatal("--watch supports a single server")
+		}
+		runBatch(cmd.Args.targets(cmd.BatchOptions), cmd.BatchOptions, cmd.ConnectionOptions, formatter,
+			table.Row{"Rule", "Value"},
+			func(client *a2s.Client, target string) (any, []table.Row, error) {
+				opts := cmd.RulesOptions
+				opts.Game = config.game(target, opts.Game)
+
+				rules, a3sbRules, err := queryRules(client, opts)
+				if err != nil {
+					return nil, nil, err
+				}
+				result, rows := rulesResult(rules, a3sbRules)
+				return result, rows, nil
+			})
+		return
+	}
+
+	if server.Host == "" {
 		fatal("Host must be provided")
 	}
 
-	client := createClient(cmd.Args.Host, cmd.Args.Port, cmd.Timeout, cmd.Buffer)
+	client := createClient(server, cmd.ConnectionOptions)
 	defer closeClient(client)
 
-	formatter := NewFormatter(cmd.Format)
+	opts := cmd.RulesOptions
+	opts.Game = config.game(server.Host, opts.Game)
+
+	if cmd.Watch > 0 {
+		runWatch(client.Address.String(), cmd.Watch, formatter, stopOnSignal(nil), func() (*watchView, error) {
+			return watchRules(client, opts)
+		})
+		return
+	}
 
+	rules, a3sbRules, err := queryRules(client, opts)
+	if err != nil {
+		fatalf("Failed to get rules: %s", err)
+	}
+
+	if a3sbRules != nil {
+		printRulesA3SB(a3sbRules, formatter, client.Address.String())
+	} else {
+		printRulesStandard(rules, formatter, client.Address.String())
+	}
+}
+
+// queryRules queries standard rules or, for Arma 3 and DayZ, A3SB rules.
+// The game is taken from options or detected via A2S_INFO unless skipped.
+func queryRules(client *a2s.Client, opts RulesOptions) (map[string]string, *a3sb.Rules, error) {
 	// Determine if we should use a3sb parser
 	useA3SB := false
 	var appID uint64
 
 	// Convert game string to AppID if specified
-	if cmd.Game != "" {
-		appID = gameToAppID(cmd.Game)
-		if appID == 0 {
-			fatalf("Unknown game: %s. Supported games: arma3, dayz", cmd.Game)
+	if opts.Game != "" {
+		var err error
+		if appID, err = gameAppID(opts.Game); err != nil {
+			return nil, nil, err
 		}
 		useA3SB = true
-	} else if !cmd.SkipInfo && !cmd.Raw {
+	} else if !opts.SkipInfo && !opts.Raw {
 		// If game not specified and skip-info is not set, try to detect from server info
 		info, err := client.GetInfo()
 		if err == nil {
@@ -62,39 +121,63 @@ func executeRules(cmd *RulesCommand) {
 		}
 	}
 
-	if useA3SB && !cmd.Raw {
-		executeRulesA3SB(client, appID, formatter)
-	} else {
-		executeRulesStandard(client, cmd.Raw, formatter)
+	if useA3SB && !opts.Raw {
+		rules, err := (&a3sb.Client{Client: client}).GetRules(appID)
+		return nil, rules, err
 	}
+
+	if opts.Raw {
+		rules, err := client.GetRules()
+		return rules, nil, err
+	}
+
+	parsedRules, err := client.GetParsedRules()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules := make(map[string]string, len(parsedRules))
+	for k, v := range parsedRules {
+		rules[k] = fmt.Sprint(v)
+	}
+
+	return rules, nil, nil
 }
 
-func executeRulesStandard(client *a2s.Client, raw bool, formatter *Formatter) {
-	var rules map[string]string
-	var err error
+// rulesResult returns queried rules and their table rows sorted by key, A3SB rules are flattened.
+func rulesResult(rules map[string]string, a3sbRules *a3sb.Rules) (any, []table.Row) {
+	if a3sbRules != nil {
+		return a3sbRules, ruleRows(flatten.Fields(nil, a3sbRules))
+	}
 
-	if raw {
-		rules, err = client.GetRules()
-	} else {
-		parsedRules, err2 := client.GetParsedRules()
-		if err2 != nil {
-			fatalf("Failed to get rules: %s", err2)
-		}
+	return rules, ruleRows(rules)
+}
 
-		rules = make(map[string]string)
-		for k, v := range parsedRules {
-			rules[k] = fmt.Sprint(v)
-		}
+// ruleRows returns rules as table rows sorted by key.
+func ruleRows(rules map[string]string) []table.Row {
+	keys := make([]string, 0, len(rules))
+	for k := range rules {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 
-	if err != nil {
-		fatalf("Failed to get rules: %s", err)
+	rows := make([]table.Row, 0, len(keys))
+	for _, k := range keys {
+		rows = append(rows, table.Row{k, rules[k]})
 	}
 
+	return rows
+}
+
+// printRulesStandard prints standard A2S_RULES response of the server at address.
+func printRulesStandard(rules map[string]string, formatter *Formatter, address string) {
 	if formatter.ShouldUseJSON() {
 		formatter.PrintJSON(rules)
 		return
 	}
+	if formatter.PrintDelimited(func(w *csvexport.Writer) error { return w.WriteRules(rules) }) {
+		return
+	}
 
 	t := table.NewWriter()
 	if formatter.IsTableFormat() {
@@ -104,49 +187,79 @@ func executeRulesStandard(client *a2s.Client, raw bool, formatter *Formatter) {
 	t.AppendHeader(table.Row{"Rule", "Value"})
 
 	// Sort keys for better output
-	keys := make([]string, 0, len(rules))
-	for k := range rules {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	for _, k := range keys {
-		t.AppendRow(table.Row{k, rules[k]})
-	}
+	t.AppendRows(ruleRows(rules))
 
 	formatter.PrintTable(t)
 	if formatter.IsTableFormat() {
-		fmt.Printf("A2S_RULES response for %s\n", client.Address)
+		fmt.Printf("A2S_RULES response for %s\n", address)
 	}
 }
 
-func executeRulesA3SB(client *a2s.Client, appID uint64, formatter *Formatter) {
-	a3sbClient := &a3sb.Client{Client: client}
+// watchRules queries rules together with A2S_INFO for the ping and returns the watch table,
+// A3SB rules have the same fields as printed by printRulesA3SB.
+func watchRules(client *a2s.Client, opts RulesOptions) (*watchView, error) {
+	all, err := queryAll(client, opts)
+	if all == nil || (all.Rules == nil && all.RawRules == nil) {
+		return nil, err
+	}
 
-	rules, err := a3sbClient.GetRules(appID)
-	if err != nil {
-		fatalf("Failed to get server rules: %s", err)
+	view := &watchView{header: table.Row{"Rule", "Value"}}
+	if all.Info != nil {
+		view.ping = all.Info.Ping
 	}
 
-	if formatter.ShouldUseJSON() {
-		formatter.PrintJSON(rules)
-		return
+	switch {
+	case all.Rules != nil:
+		view.header, view.rows, view.key = table.Row{"Section", "Name", "Value"}, a3sbWatchRows(all.Rules), 1
+	case opts.Raw:
+		view.rows = ruleRows(all.RawRules)
+	default:
+		rules := make(map[string]string, len(all.RawRules))
+		for k, v := range a2s.ParseRuleValues(all.RawRules) {
+			rules[k] = fmt.Sprint(v)
+		}
+		view.rows = ruleRows(rules)
 	}
 
-	// Print Island/Description info (DayZ specific)
-	if rules.Island != "" {
-		formatter.PrintSectionHeader("Server Information")
-		t := table.NewWriter()
-		if formatter.IsTableFormat() {
-			t.SetOutputMirror(os.Stdout)
+	return view, nil
+}
+
+// a3sbWatchRows returns rows of the A3SB rule sections as section, name and value,
+// list positions are dropped so that a removed mod does not change every following row.
+func a3sbWatchRows(rules *a3sb.Rules) []table.Row {
+	var rows []table.Row
+	for _, section := range a3sbRuleSections(rules) {
+		for _, row := range section.rows {
+			if section.header[0] == "#" {
+				row = row[1:]
+			}
+			rows = append(rows, append(table.Row{section.title}, row...))
 		}
-		t.SetStyle(table.StyleRounded)
-		t.AppendHeader(table.Row{"Option", "Value"})
+	}
+
+	return rows
+}
 
+// ruleSection is a titled table of A3SB rules.
+type ruleSection struct {
+	title  string
+	header table.Row
+	rows   []table.Row
+}
+
+// a3sbRuleSections returns the tables of A3SB rules: DayZ server information, Arma 3 difficulty,
+// DLC, Creator DLC and mods. Empty sections are omitted.
+func a3sbRuleSections(rules *a3sb.Rules) []ruleSection {
+	var sections []ruleSection
+
+	// Island/Description info (DayZ specific)
+	if rules.Island != "" {
+		var rows []table.Row
 		if rules.Description != "" {
-			t.AppendRow(table.Row{"Description:", rules.Description})
+			rows = append(rows, table.Row{"Description:", rules.Description})
 		}
 
-		t.AppendRows([]table.Row{
+		rows = append(rows, []table.Row{
 			{"Allowed build:", fmt.Sprintf("%d", rules.AllowedBuild)},
 			{"Client port:", fmt.Sprintf("%d", rules.ClientPort)},
 			{"Dedicated:", fmt.Sprintf("%t", rules.Dedicated)},
@@ -156,95 +269,71 @@ func executeRulesA3SB(client *a2s.Client, appID uint64, formatter *Formatter) {
 			{"Required build:", fmt.Sprintf("%d", rules.RequiredBuild)},
 			{"Required version:", fmt.Sprintf("%d", rules.RequiredVersion)},
 			{"TimeLeft:", fmt.Sprintf("%d", rules.TimeLeft)},
-		})
+		}...)
 
-		formatter.PrintTable(t)
+		sections = append(sections, ruleSection{title: "Server Information", header: table.Row{"Option", "Value"}, rows: rows})
 	}
 
-	// Print Difficulty (Arma3 specific)
+	// Difficulty (Arma3 specific)
 	if rules.Difficulty != nil {
-		formatter.PrintSectionHeader("Difficulty Settings")
-		t := table.NewWriter()
-		if formatter.IsTableFormat() {
-			t.SetOutputMirror(os.Stdout)
-		}
-		t.SetStyle(table.StyleRounded)
-		t.AppendHeader(table.Row{"Option", "Value"})
-		t.AppendRows([]table.Row{
+		sections = append(sections, ruleSection{title: "Difficulty Settings", header: table.Row{"Option", "Value"}, rows: []table.Row{
 			{"Difficulty Level:", fmt.Sprintf("%d", rules.Difficulty.Level)},
 			{"AI Level:", fmt.Sprintf("%d", rules.Difficulty.AILevel)},
 			{"Advanced Flight:", fmt.Sprintf("%t", rules.Difficulty.AdvanceFlight)},
 			{"Third Person:", fmt.Sprintf("%t", rules.Difficulty.ThirdPerson)},
 			{"Crosshair:", fmt.Sprintf("%t", rules.Difficulty.Crosshair)},
-		})
-		formatter.PrintTable(t)
+		}})
 	}
 
-	// Print DLC
-	if len(rules.DLC) > 0 {
-		formatter.PrintSectionHeader("DLC")
-		t := table.NewWriter()
-		if formatter.IsTableFormat() {
-			t.SetOutputMirror(os.Stdout)
-		}
-		t.SetStyle(table.StyleRounded)
-		t.AppendHeader(table.Row{"#", "DLC Name", "DLC URL"})
-
-		for i, dlc := range rules.DLC {
-			t.AppendRow(table.Row{
-				fmt.Sprintf("%d", i+1),
-				dlc.Name,
-				fmt.Sprintf("https://store.steampowered.com/app/%d", dlc.ID),
-			})
+	dlcRows := func(list []a3sb.DLCInfo) []table.Row {
+		rows := make([]table.Row, 0, len(list))
+		for i, dlc := range list {
+			rows = append(rows, table.Row{fmt.Sprintf("%d", i+1), dlc.Name, a3sb.StoreURL + strconv.FormatUint(dlc.ID, 10)})
 		}
-
-		formatter.PrintTable(t)
+		return rows
+	}
+	if len(rules.DLC) > 0 {
+		sections = append(sections, ruleSection{title: "DLC", header: table.Row{"#", "DLC Name", "DLC URL"}, rows: dlcRows(rules.DLC)})
 	}
-
-	// Print Creator DLC
 	if len(rules.CreatorDLC) > 0 {
-		formatter.PrintSectionHeader("Creator DLC")
-		t := table.NewWriter()
-		if formatter.IsTableFormat() {
-			t.SetOutputMirror(os.Stdout)
-		}
-		t.SetStyle(table.StyleRounded)
-		t.AppendHeader(table.Row{"#", "Creator DLC Name", "Creator DLC URL"})
+		sections = append(sections, ruleSection{title: "Creator DLC", header: table.Row{"#", "Creator DLC Name", "Creator DLC URL"}, rows: dlcRows(rules.CreatorDLC)})
+	}
 
-		for i, dlc := range rules.CreatorDLC {
-			t.AppendRow(table.Row{
-				fmt.Sprintf("%d", i+1),
-				dlc.Name,
-				fmt.Sprintf("https://store.steampowered.com/app/%d", dlc.ID),
-			})
+	if len(rules.Mods) > 0 {
+		rows := make([]table.Row, 0, len(rules.Mods))
+		for i, mod := range rules.Mods {
+			rows = append(rows, table.Row{fmt.Sprintf("%d", i+1), mod.Name, a3sb.WorkshopURL + strconv.FormatUint(mod.ID, 10)})
 		}
+		sections = append(sections, ruleSection{title: "Mods", header: table.Row{"#", "Mod Name", "Mod URL"}, rows: rows})
+	}
 
-		formatter.PrintTable(t)
+	return sections
+}
+
+// printRulesA3SB prints A3SB rules of the server at address.
+func printRulesA3SB(rules *a3sb.Rules, formatter *Formatter, address string) {
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(rules)
+		return
+	}
+	if formatter.PrintDelimited(func(w *csvexport.Writer) error { return w.WriteA3SBRules(rules) }) {
+		return
 	}
 
-	// Print Mods
-	if len(rules.Mods) > 0 {
-		formatter.PrintSectionHeader("Mods")
+	for _, section := range a3sbRuleSections(rules) {
+		formatter.PrintSectionHeader(section.title)
 		t := table.NewWriter()
 		if formatter.IsTableFormat() {
 			t.SetOutputMirror(os.Stdout)
 		}
 		t.SetStyle(table.StyleRounded)
-		t.AppendHeader(table.Row{"#", "Mod Name", "Mod URL"})
-
-		for i, mod := range rules.Mods {
-			t.AppendRow(table.Row{
-				fmt.Sprintf("%d", i+1),
-				mod.Name,
-				fmt.Sprintf("https://steamcommunity.com/sharedfiles/filedetails/?id=%d", mod.ID),
-			})
-		}
-
+		t.AppendHeader(section.header)
+		t.AppendRows(section.rows)
 		formatter.PrintTable(t)
 	}
 
 	// Only print footer message for table format
 	if formatter.IsTableFormat() {
-		fmt.Printf("A2S_RULES response for %s\n", client.Address)
+		fmt.Printf("A2S_RULES response for %s\n", address)
 	}
 }
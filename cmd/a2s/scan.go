@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/woozymasta/a2s/pkg/discovery"
+	"github.com/woozymasta/a2s/pkg/filter"
+)
+
+func executeScan(cmd *ScanCommand) {
+	networks := make([]netip.Prefix, 0, len(cmd.Args.Networks))
+	for _, value := range cmd.Args.Networks {
+		network, err := discovery.ParseNetwork(value)
+		if err != nil {
+			fatalf("Failed to parse network: %s", err)
+		}
+		networks = append(networks, network)
+	}
+
+	ports, err := discovery.ParsePorts(cmd.Ports)
+	if err != nil {
+		fatalf("Failed to parse ports: %s", err)
+	}
+
+	match := compileFilter(cmd.Filter)
+
+	opts := discovery.ScanOptions{
+		Networks:    networks,
+		Ports:       ports,
+		Timeout:     time.Duration(cmd.Timeout) * time.Millisecond,
+		Rate:        cmd.Rate,
+		Concurrency: cmd.Concurrency,
+	}
+
+	if cmd.Format == "jsonl" {
+		encoder := json.NewEncoder(os.Stdout)
+		opts.OnFound = func(result discovery.Result) {
+			if !match(filter.Server{Info: result.Info}) {
+				return
+			}
+			if err := encoder.Encode(result); err != nil {
+				fatalf("Failed to write result: %s", err)
+			}
+		}
+	}
+
+	results, err := discovery.Scan(opts)
+	if err != nil {
+		fatalf("Failed to scan: %s", err)
+	}
+
+	if cmd.Format == "jsonl" {
+		return
+	}
+
+	results = slices.DeleteFunc(results, func(result discovery.Result) bool {
+		return !match(filter.Server{Info: result.Info})
+	})
+
+	printDiscovered(results, NewFormatter(cmd.Format, cmd.TemplateOptions))
+}
+
+// printDiscovered prints servers found by discovery.
+func printDiscovered(results []discovery.Result, formatter *Formatter) {
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(results)
+		return
+	}
+
+	t := formatter.NewTable()
+	t.AppendHeader(table.Row{"#", "Address", "Name", "Map", "Players", "AppID", "Version", "Ping"})
+	for i, result := range results {
+		info := result.Info
+		t.AppendRow(table.Row{
+			fmt.Sprintf("%d", i+1),
+			result.Address,
+			info.Name,
+			info.Map,
+			fmt.Sprintf("%d/%d", info.Players, info.MaxPlayers),
+			fmt.Sprintf("%d", info.ID),
+			info.Version,
+			fmt.Sprintf("%d ms", info.Ping.Milliseconds()),
+		})
+	}
+	formatter.PrintTable(t)
+
+	if formatter.IsTableFormat() {
+		fmt.Printf("Found %d servers\n", len(results))
+	}
+}
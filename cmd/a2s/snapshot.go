@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/woozymasta/a2s/pkg/snapshot"
+)
+
+func executeSnapshot(cmd *SnapshotCommand) {
+	if cmd.Args.Host == "" {
+		fatal("Host must be provided")
+	}
+
+	snap := takeSnapshot(resolveAddress(cmd.Args, cmd.ConnectionOptions))
+
+	if cmd.Output == "" {
+		if err := snap.Write(os.Stdout); err != nil {
+			fatalf("Failed to write snapshot: %s", err)
+		}
+		return
+	}
+
+	if err := snap.Save(cmd.Output); err != nil {
+		fatalf("Failed to save snapshot: %s", err)
+	}
+}
+
+func executeDiff(cmd *DiffCommand) {
+	old, err := snapshot.Load(cmd.Args.Old)
+	if err != nil {
+		fatalf("Failed to load snapshot: %s", err)
+	}
+
+	var current *snapshot.Snapshot
+	switch target := cmd.Args.New; {
+	case target == "":
+		current = takeSnapshot(old.Address, cmd.ConnectionOptions)
+	case isFile(target):
+		if current, err = snapshot.Load(target); err != nil {
+			fatalf("Failed to load snapshot: %s", err)
+		}
+	default:
+		current = takeSnapshot(target, cmd.ConnectionOptions)
+	}
+
+	if cmd.Save != "" {
+		if err := current.Save(cmd.Save); err != nil {
+			fatalf("Failed to save snapshot: %s", err)
+		}
+	}
+
+	changes := snapshot.Diff(old, current)
+
+	formatter := NewFormatter(cmd.Format, cmd.TemplateOptions)
+	if formatter.ShouldUseJSON() {
+		formatter.PrintJSON(changes)
+	} else {
+		printChanges(changes, formatter)
+		if formatter.IsTableFormat() {
+			fmt.Printf("%d changes from %s (%s) to %s (%s)\n", len(changes),
+				old.Address, old.Time.Local().Format(time.DateTime),
+				current.Address, current.Time.Local().Format(time.DateTime))
+		}
+	}
+
+	if len(changes) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printChanges(changes []snapshot.Change, formatter *Formatter) {
+	if len(changes) == 0 {
+		return
+	}
+
+	formatter.PrintSectionHeader("Changes")
+	t := formatter.NewTable()
+	t.AppendHeader(table.Row{"Category", "Field", "Change", "Old", "New"})
+	for _, change := range changes {
+		t.AppendRow(table.Row{change.Category, change.Field, change.Kind, orAbsent(change.Old), orAbsent(change.New)})
+	}
+	formatter.PrintTable(t)
+}
+
+// takeSnapshot captures the live server state or exits on error.
+func takeSnapshot(address string, conn ConnectionOptions) *snapshot.Snapshot {
+	address, conn, err := config.server(address, conn)
+	if err != nil {
+		fatal(err)
+	}
+
+	snap, err := snapshot.Take(address, snapshot.Options{
+		Timeout:    time.Duration(conn.Timeout) * time.Second,
+		BufferSize: conn.Buffer,
+		Dialer:     proxyDialer(conn),
+	})
+	if err != nil {
+		fatalf("Failed to take snapshot of %s: %s", address, err)
+	}
+
+	return snap
+}
+
+// isFile reports whether path is an existing regular file.
+func isFile(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && stat.Mode().IsRegular()
+}
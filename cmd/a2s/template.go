@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateOptions defines options of the template output format.
+type TemplateOptions struct {
+	Template     string `long:"template" description:"Go template for the template format, e.g. '{{.Name}} {{.Players}}/{{.MaxPlayers}}' (implies --format template)"`
+	TemplateFile string `long:"template-file" description:"File with Go template for the template format (implies --format template)"`
+}
+
+// colorCodes matches ANSI escapes, Quake style ^N codes and rich text tags used in server and player names.
+var colorCodes = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]|\^[0-9]|</?(?:color|b|i|u|size)(?:=[^>]*)?>`)
+
+// templateFuncs are helper functions available in output templates.
+var templateFuncs = template.FuncMap{
+	"duration":    formatDuration,
+	"seconds":     func(d time.Duration) int64 { return int64(d / time.Second) },
+	"ms":          func(d time.Duration) int64 { return d.Milliseconds() },
+	"bytes":       formatBytes,
+	"stripColors": func(s string) string { return colorCodes.ReplaceAllString(s, "") },
+	"join":        func(sep string, values []string) string { return strings.Join(values, sep) },
+	"lower":       strings.ToLower,
+	"upper":       strings.ToUpper,
+	"trim":        strings.TrimSpace,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// parseTemplate returns the template from options, nil if none is set.
+func parseTemplate(opts TemplateOptions) *template.Template {
+	text := opts.Template
+	switch {
+	case opts.Template != "" && opts.TemplateFile != "":
+		fatal("--template and --template-file are mutually exclusive")
+
+	case opts.TemplateFile != "":
+		data, err := os.ReadFile(opts.TemplateFile)
+		if err != nil {
+			fatalf("Failed to read template: %s", err)
+		}
+		text = string(data)
+	}
+
+	if text == "" {
+		return nil
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		fatalf("Failed to parse template: %s", err)
+	}
+
+	return tmpl
+}
+
+// formatDuration formats duration rounded to milliseconds below a minute and to seconds above, e.g. 42ms or 1h2m3s.
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(time.Second).String()
+}
+
+// formatBytes formats a size in bytes with binary units, e.g. 1.5 KiB.
+func formatBytes(size any) string {
+	var n float64
+	switch v := size.(type) {
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case uint16:
+		n = float64(v)
+	case uint32:
+		n = float64(v)
+	case uint64:
+		n = float64(v)
+	case float64:
+		n = v
+	default:
+		return fmt.Sprint(size)
+	}
+
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	unit := 0
+	for n >= 1024 && unit < len(units)-1 {
+		n /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", n, units[unit])
+	}
+
+	return fmt.Sprintf("%.1f %s", n, units[unit])
+}
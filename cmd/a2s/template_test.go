@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		data any
+		text string
+		want string
+	}{
+		{text: `{{stripColors .}}`, data: "^1Red ^7<color=#ff0000>Server</color> \x1b[1;31mX\x1b[0m", want: "Red Server X"},
+		{text: `{{duration .}}`, data: 42*time.Millisecond + 300*time.Microsecond, want: "42ms"},
+		{text: `{{duration .}}`, data: time.Hour + 2*time.Minute + 3500*time.Millisecond, want: "1h2m4s"},
+		{text: `{{seconds .}}`, data: 90 * time.Second, want: "90"},
+		{text: `{{bytes .}}`, data: 512, want: "512 B"},
+		{text: `{{bytes .}}`, data: uint64(1536), want: "1.5 KiB"},
+		{text: `{{bytes .}}`, data: int64(5 << 20), want: "5.0 MiB"},
+		{text: `{{join ", " .}}`, data: []string{"a", "b"}, want: "a, b"},
+		{text: `{{json .}}`, data: map[string]int{"a": 1}, want: `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		tmpl := parseTemplate(TemplateOptions{Template: tt.text})
+
+		var out strings.Builder
+		if err := tmpl.Execute(&out, tt.data); err != nil {
+			t.Fatalf("%s: %v", tt.text, err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s = %q, want %q", tt.text, out.String(), tt.want)
+		}
+	}
+}
+
+func TestInfoViewTemplate(t *testing.T) {
+	info := &a2s.Info{Name: "DayZ", Players: 5, MaxPlayers: 60, ID: 221100, Keywords: []string{"battleye", "no3rd"}}
+	tmpl := parseTemplate(TemplateOptions{Template: `{{.Name}} {{.Players}}/{{.MaxPlayers}} {{.Keywords.NoThirdPerson}}`})
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, newInfoView(info)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "DayZ 5/60 true"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// WatchOptions defines options of the watch mode.
+type WatchOptions struct {
+	Watch time.Duration `long:"watch" value-name:"interval" description:"Query every interval, e.g. 2s, redrawing the table in place with changes highlighted"`
+}
+
+// watchView is the table of a single refresh in watch mode.
+type watchView struct {
+	header   table.Row
+	rows     []table.Row
+	volatile []int         // Columns ignored when detecting changed rows, e.g. play time
+	key      int           // Column identifying a row, e.g. player name, -1 for row position
+	ping     time.Duration // Round trip time of the request
+}
+
+// watchQuery queries the server and returns the table of a refresh.
+type watchQuery func() (*watchView, error)
+
+// Row highlight colors of watch mode.
+var (
+	watchAdded   = text.Colors{text.FgGreen}
+	watchChanged = text.Colors{text.FgYellow}
+	watchRemoved = text.Colors{text.FgRed, text.CrossedOut}
+)
+
+// runWatch queries the server every interval and redraws the table in place until stop is closed.
+// Rows added since the last refresh (joined players) are green, changed rows yellow and
+// removed rows (left players) red for one refresh. The header shows the ping of the last
+// successful query, the uptime of the watch and the error count, the last table is kept on errors.
+func runWatch(address string, interval time.Duration, formatter *Formatter, stop <-chan struct{}, query watchQuery) {
+	if !formatter.IsTableFormat() && formatter.GetFormat() != "raw" {
+		fatal("--watch supports table and raw formats only")
+	}
+
+	var (
+		prev, last *watchView
+		rows       []table.Row
+		lastErr    error
+		errCount   int
+	)
+
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		view, err := query()
+		if err != nil {
+			errCount++
+			lastErr = err
+		} else {
+			lastErr = nil
+			rows = diffRows(prev, view)
+			prev, last = view, view
+		}
+
+		// Clear the screen and move the cursor home
+		fmt.Print("\x1b[H\x1b[2J")
+		var ping time.Duration
+		if last != nil {
+			ping = last.ping
+		}
+		fmt.Printf("Every %s: %s  ping %d ms  up %s  errors %d\n",
+			interval, address, ping.Milliseconds(), time.Since(start).Round(time.Second), errCount)
+		if lastErr != nil {
+			fmt.Println(watchRemoved.Sprint("Error: " + lastErr.Error()))
+		}
+
+		if last != nil {
+			t := formatter.NewTable()
+			t.AppendHeader(last.header)
+			t.AppendRows(rows)
+			formatter.PrintTable(t)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// diffRows returns rows of the current view highlighted against the previous one,
+// followed by removed rows. Nothing is highlighted on the first refresh.
+func diffRows(prev, cur *watchView) []table.Row {
+	if prev == nil {
+		return cur.rows
+	}
+
+	prevRows := make(map[string]table.Row, len(prev.rows))
+	for i, key := range rowKeys(prev) {
+		prevRows[key] = prev.rows[i]
+	}
+
+	rows := make([]table.Row, 0, len(cur.rows))
+	for i, key := range rowKeys(cur) {
+		row := cur.rows[i]
+		old, ok := prevRows[key]
+		delete(prevRows, key)
+
+		switch {
+		case !ok:
+			row = paintRow(row, watchAdded)
+		case rowValue(old, cur.volatile) != rowValue(row, cur.volatile):
+			row = paintRow(row, watchChanged)
+		}
+		rows = append(rows, row)
+	}
+
+	for i, key := range rowKeys(prev) {
+		if _, ok := prevRows[key]; ok {
+			rows = append(rows, paintRow(prev.rows[i], watchRemoved))
+		}
+	}
+
+	return rows
+}
+
+// rowKeys returns identifying keys of rows, repeated keys are numbered.
+func rowKeys(view *watchView) []string {
+	keys := make([]string, len(view.rows))
+	seen := make(map[string]int, len(view.rows))
+
+	for i, row := range view.rows {
+		key := strconv.Itoa(i)
+		if view.key >= 0 && view.key < len(row) {
+			key = fmt.Sprint(row[view.key])
+		}
+
+		seen[key]++
+		if seen[key] > 1 {
+			key += "#" + strconv.Itoa(seen[key])
+		}
+		keys[i] = key
+	}
+
+	return keys
+}
+
+// rowValue joins cells of the row except volatile columns for comparison.
+func rowValue(row table.Row, volatile []int) string {
+	cells := make([]string, 0, len(row))
+	for i, cell := range row {
+		if !slices.Contains(volatile, i) {
+			cells = append(cells, fmt.Sprint(cell))
+		}
+	}
+
+	return strings.Join(cells, "\x00")
+}
+
+// paintRow returns a copy of the row with all cells colored.
+func paintRow(row table.Row, colors text.Colors) table.Row {
+	painted := make(table.Row, len(row))
+	for i, cell := range row {
+		painted[i] = colors.Sprint(fmt.Sprint(cell))
+	}
+
+	return painted
+}
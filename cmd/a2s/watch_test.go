@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a2stest"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+)
+
+func TestDiffRows(t *testing.T) {
+	header := table.Row{"#", "PlayTime", "Score", "Name"}
+	view := func(rows ...table.Row) *watchView {
+		return &watchView{header: header, rows: rows, key: 3, volatile: []int{0, 1}}
+	}
+
+	prev := view(
+		table.Row{"1", "1m0s", "7", "Alice"},
+		table.Row{"2", "2m0s", "3", "Bob"},
+		table.Row{"3", "3m0s", "1", "Carol"},
+	)
+	cur := view(
+		table.Row{"1", "1m2s", "7", "Alice"},
+		table.Row{"2", "3m2s", "2", "Carol"},
+		table.Row{"3", "0s", "0", "Dave"},
+	)
+
+	if rows := diffRows(nil, prev); len(rows) != 3 || rows[0][3] != "Alice" {
+		t.Errorf("first refresh rows = %v", rows)
+	}
+
+	rows := diffRows(prev, cur)
+	want := []struct {
+		colors text.Colors
+		name   string
+	}{
+		{name: "Alice"},
+		{colors: watchChanged, name: "Carol"},
+		{colors: watchAdded, name: "Dave"},
+		{colors: watchRemoved, name: "Bob"},
+	}
+
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %d, want %d: %v", len(rows), len(want), rows)
+	}
+	for i, w := range want {
+		name := w.name
+		if w.colors != nil {
+			name = w.colors.Sprint(name)
+		}
+		if got := fmt.Sprint(rows[i][3]); got != name {
+			t.Errorf("row %d name = %q, want %q", i, got, name)
+		}
+	}
+}
+
+func TestRowKeys(t *testing.T) {
+	view := &watchView{rows: []table.Row{{"a"}, {"b"}, {"a"}}}
+	if keys := rowKeys(view); keys[0] != "a" || keys[2] != "a#2" {
+		t.Errorf("rowKeys() = %v", keys)
+	}
+
+	view.key = -1
+	if keys := rowKeys(view); keys[0] != "0" || keys[2] != "2" {
+		t.Errorf("rowKeys() by position = %v", keys)
+	}
+}
+
+func TestRunWatchStop(t *testing.T) {
+	stdout := os.Stdout
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { os.Stdout = stdout; _ = devNull.Close() }()
+	os.Stdout = devNull
+
+	stop := make(chan struct{})
+	close(stop)
+
+	queries := 0
+	runWatch("127.0.0.1:27016", time.Hour, NewFormatter("table", TemplateOptions{}), stop, func() (*watchView, error) {
+		queries++
+		return &watchView{header: table.Row{"Property", "Value"}, rows: []table.Row{{"Name", "a2s"}}}, nil
+	})
+
+	if queries != 1 {
+		t.Errorf("runWatch made %d queries after stop, want 1", queries)
+	}
+}
+
+func TestA3SBWatchRows(t *testing.T) {
+	rules := &a3sb.Rules{
+		Island:      "chernarusplus",
+		Description: "Vanilla",
+		TimeLeft:    15,
+		Difficulty:  &a3sb.Difficulty{Level: 2},
+		Mods:        []a3sb.Mod{{Name: "CF", ID: 1559212036}},
+	}
+
+	values := make(map[string]string)
+	for _, row := range a3sbWatchRows(rules) {
+		if len(row) != 3 {
+			t.Fatalf("row %v, want section, name and value", row)
+		}
+		values[fmt.Sprint(row[0], "/", row[1])] = fmt.Sprint(row[2])
+	}
+
+	for key, want := range map[string]string{
+		"Server Information/Island:":            "chernarusplus",
+		"Server Information/Description:":       "Vanilla",
+		"Server Information/TimeLeft:":          "15",
+		"Difficulty Settings/Difficulty Level:": "2",
+		"Mods/CF":                               a3sb.WorkshopURL + "1559212036",
+	} {
+		if values[key] != want {
+			t.Errorf("%s = %q, want %q", key, values[key], want)
+		}
+	}
+}
+
+func TestWatchRulesPing(t *testing.T) {
+	server, err := a2stest.NewServer(a2stest.Normal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }()
+
+	client, err := a2s.NewWithAddr(server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	client.Timeout = time.Second
+
+	view, err := watchRules(client, RulesOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.ping <= 0 || len(view.rows) != len(a2stest.Rules) {
+		t.Errorf("watchRules() ping %s, %d rows", view.ping, len(view.rows))
+	}
+}
@@ -10,8 +10,9 @@ import (
 
 // Reader provides efficient byte reading from []byte without buffer allocations.
 type Reader struct {
-	data []byte
-	pos  int
+	data      []byte
+	pos       int
+	maxString int
 }
 
 // NewReader creates a new reader from []byte
@@ -19,6 +20,11 @@ func NewReader(data []byte) *Reader {
 	return &Reader{data: data, pos: 0}
 }
 
+// SetMaxString limits the length of strings read, 0 disables the limit
+func (r *Reader) SetMaxString(size int) {
+	r.maxString = size
+}
+
 // Reset resets the reader to the beginning
 func (r *Reader) Reset(data []byte) {
 	r.data = data
@@ -122,6 +128,9 @@ func (r *Reader) String() (string, error) {
 	if r.pos >= len(r.data) {
 		return "", ErrString
 	}
+	if r.maxString > 0 && r.pos-start > r.maxString {
+		return "", ErrStringLen
+	}
 
 	str := string(r.data[start:r.pos])
 	r.pos++
@@ -138,6 +147,9 @@ func (r *Reader) BytesPage() ([]byte, error) {
 	if r.pos >= len(r.data) {
 		return nil, ErrString
 	}
+	if r.maxString > 0 && r.pos-start > r.maxString {
+		return nil, ErrStringLen
+	}
 
 	result := r.data[start:r.pos]
 	r.pos++
@@ -146,6 +158,9 @@ func (r *Reader) BytesPage() ([]byte, error) {
 
 // StringLen reads a string of specified length.
 func (r *Reader) StringLen(size int) (string, error) {
+	if r.maxString > 0 && size > r.maxString {
+		return "", ErrStringLen
+	}
 	if r.pos+size > len(r.data) {
 		return "", ErrUnderflow
 	}
@@ -0,0 +1,80 @@
+// Package flatten converts server info and A3SB rules into flat named fields
+// shared by fleet comparison, snapshot diffs and rule tables.
+package flatten
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/keywords"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+// Fields converts server info and A3SB rules into named fields, e.g. "version", "mods.1559212036",
+// "signatures.a3" or "keywords.battleye".
+// Absent values (mods, signatures, DLC not present, empty versions) are omitted.
+func Fields(info *a2s.Info, rules *a3sb.Rules) map[string]string {
+	flat := make(map[string]string, 32)
+	set := func(field, value string) {
+		if value != "" {
+			flat[field] = value
+		}
+	}
+
+	if info != nil {
+		set("version", info.Version)
+		set("app_id", strconv.FormatUint(info.ID, 10))
+
+		switch info.ID {
+		case appid.Arma3.Uint64():
+			kw := keywords.ParseArma3(info.Keywords)
+			set("keywords.battleye", strconv.FormatBool(kw.BattlEye))
+			set("keywords.verify_signatures", strconv.FormatBool(kw.VerifySignatures))
+			set("keywords.equal_mod_required", strconv.FormatBool(kw.EqualModRequired))
+			set("keywords.file_patching", strconv.FormatBool(kw.AllowedFilePatching))
+			set("keywords.required_version", strconv.FormatUint(uint64(kw.RequiredVersion), 10))
+			set("keywords.required_build", strconv.FormatUint(uint64(kw.RequiredBuildNo), 10))
+			set("keywords.difficulty", strconv.Itoa(int(kw.Difficulty)))
+
+		case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
+			kw := keywords.ParseDayZ(info.Keywords)
+			set("keywords.battleye", strconv.FormatBool(kw.BattlEye))
+			set("keywords.no3rd", strconv.FormatBool(kw.NoThirdPerson))
+			set("keywords.external", strconv.FormatBool(kw.External))
+			set("keywords.private_hive", strconv.FormatBool(kw.PrivateHive))
+			set("keywords.modded", strconv.FormatBool(kw.Modded))
+			set("keywords.whitelist", strconv.FormatBool(kw.Whitelist))
+			set("keywords.file_patching", strconv.FormatBool(kw.FlePatching))
+			set("keywords.dlc", strconv.FormatBool(kw.DLC))
+		}
+	}
+
+	if rules != nil {
+		if rules.RequiredVersion != 0 {
+			set("required_version", strconv.FormatUint(uint64(rules.RequiredVersion), 10))
+		}
+		if rules.RequiredBuild != 0 {
+			set("required_build", strconv.FormatUint(uint64(rules.RequiredBuild), 10))
+		}
+		if rules.AllowedBuild != 0 {
+			set("allowed_build", strconv.FormatUint(uint64(rules.AllowedBuild), 10))
+		}
+
+		for _, mod := range rules.Mods {
+			set("mods."+strconv.FormatUint(mod.ID, 10), fmt.Sprintf("%s (hash %08x)", mod.DisplayName(), mod.Hash))
+		}
+		for _, signature := range rules.Signatures {
+			set("signatures."+signature, "present")
+		}
+		for _, dlc := range rules.DLC {
+			set("dlc."+dlc.Name, fmt.Sprintf("hash %08x", dlc.Hash))
+		}
+		for _, dlc := range rules.CreatorDLC {
+			set("creator_dlc."+strconv.FormatUint(dlc.ID, 10), "present")
+		}
+	}
+
+	return flat
+}
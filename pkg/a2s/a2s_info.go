@@ -3,8 +3,6 @@ package a2s
 import (
 	"errors"
 	"time"
-
-	"github.com/woozymasta/a2s/internal/bread"
 )
 
 // Info contains A2S_INFO response data.
@@ -52,7 +50,12 @@ func (c *Client) GetInfo() (*Info, error) {
 	c.parseData = c.parseData[:len(data)]
 	copy(c.parseData, data)
 
-	reader := bread.NewReader(c.parseData)
+	return parseInfo(c.parseData, format, duration, c.Limits)
+}
+
+// parseInfo parses A2S_INFO response data of the given format.
+func parseInfo(data []byte, format Flag, duration time.Duration, limits Limits) (*Info, error) {
+	reader := limits.reader(data)
 	info := &Info{Ping: duration, Format: InfoFormat(format)}
 
 	switch format {
@@ -3,8 +3,6 @@ package a2s
 import (
 	"errors"
 	"time"
-
-	"github.com/woozymasta/a2s/internal/bread"
 )
 
 // Player contains player information from A2S_PLAYER query.
@@ -29,11 +27,24 @@ func (c *Client) GetPlayers() (*[]Player, error) {
 	c.parseData = c.parseData[:len(data)]
 	copy(c.parseData, data)
 
-	reader := bread.NewReader(c.parseData)
+	players, err := parsePlayers(c.parseData, c.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &players, nil
+}
+
+// parsePlayers parses A2S_PLAYER response data.
+func parsePlayers(data []byte, limits Limits) ([]Player, error) {
+	reader := limits.reader(data)
 	count, err := reader.Byte()
 	if err != nil {
 		return nil, errors.Join(ErrPlayerCount, err)
 	}
+	if err := limits.checkPlayers(int(count)); err != nil {
+		return nil, err
+	}
 
 	players := make([]Player, 0, int(count))
 
@@ -59,5 +70,5 @@ func (c *Client) GetPlayers() (*[]Player, error) {
 		players = append(players, player)
 	}
 
-	return &players, nil
+	return players, nil
 }
@@ -5,8 +5,6 @@ import (
 	"errors"
 	"strconv"
 	"unicode/utf8"
-
-	"github.com/woozymasta/a2s/internal/bread"
 )
 
 // GetRules queries server rules (A2S_RULES).
@@ -23,11 +21,19 @@ func (c *Client) GetRules() (map[string]string, error) {
 	c.parseData = c.parseData[:len(data)]
 	copy(c.parseData, data)
 
-	reader := bread.NewReader(c.parseData)
+	return parseRules(c.parseData, c.Limits)
+}
+
+// parseRules parses A2S_RULES response data.
+func parseRules(data []byte, limits Limits) (map[string]string, error) {
+	reader := limits.reader(data)
 	count, err := reader.Uint16()
 	if err != nil {
 		return nil, errors.Join(ErrRuleCount, err)
 	}
+	if err := limits.CheckRules(int(count)); err != nil {
+		return nil, err
+	}
 
 	if count == 0 {
 		return nil, nil
@@ -70,11 +76,14 @@ func (c *Client) GetParsedRules() (map[string]any, error) {
 	c.parseData = c.parseData[:len(data)]
 	copy(c.parseData, data)
 
-	reader := bread.NewReader(c.parseData)
+	reader := c.Limits.reader(c.parseData)
 	count, err := reader.Uint16()
 	if err != nil {
 		return nil, errors.Join(ErrRuleCount, err)
 	}
+	if err := c.Limits.CheckRules(int(count)); err != nil {
+		return nil, err
+	}
 
 	if count == 0 {
 		return nil, nil
@@ -106,6 +115,21 @@ func (c *Client) GetParsedRules() (map[string]any, error) {
 	return rules, nil
 }
 
+// ParseRuleValues parses values of standard rules the same way as GetParsedRules.
+func ParseRuleValues(rules map[string]string) map[string]any {
+	if rules == nil {
+		return nil
+	}
+
+	parsed := make(map[string]any, len(rules))
+	var base64Buf []byte
+	for key, value := range rules {
+		parsed[key] = parseRuleValue(value, &base64Buf)
+	}
+
+	return parsed
+}
+
 // parseRuleValue attempts to parse value string into int64, float64, bool, or base64-decoded string.
 // Uses reusable base64Buf to minimize allocations.
 func parseRuleValue(v string, base64Buf *[]byte) any {
@@ -4,18 +4,26 @@ import (
 	"encoding/binary"
 	"errors"
 	"net"
+	"slices"
 	"time"
 )
 
+// DialFunc opens a datagram connection to the server address, e.g. through a proxy.
+// Every Write must send one request datagram and every Read must return one response datagram.
+type DialFunc func(addr *net.UDPAddr) (net.Conn, error)
+
 // Client handles UDP connection and A2S protocol queries.
 type Client struct {
-	Conn       *net.UDPConn
-	Address    *net.UDPAddr
-	packetsBuf map[int][]byte
-	parseData  []byte
-	readBuf    []byte
-	Timeout    time.Duration
-	BufferSize uint16
+	Conn            net.Conn
+	Address         *net.UDPAddr
+	Dialer          DialFunc // Custom transport used by Dial, direct UDP if nil
+	Limits          Limits   // Decoding limits, DefaultLimits for zero fields
+	parseData       []byte
+	readBuf         []byte
+	staleSplits     []uint32      // Split IDs left incomplete by previous queries, oldest first
+	Timeout         time.Duration // Overall response timeout
+	FragmentTimeout time.Duration // Inactivity timeout between split fragments, disabled if zero
+	BufferSize      uint16
 }
 
 // New creates a new client with IP and port and opens UDP connection.
@@ -35,10 +43,17 @@ func NewWithString(addr string) (*Client, error) {
 
 // NewWithAddr creates a new client with address and opens UDP connection.
 func NewWithAddr(addr *net.UDPAddr) (*Client, error) {
+	return NewWithDialer(addr, nil)
+}
+
+// NewWithDialer creates a new client with address and opens connection with dialer,
+// e.g. through a SOCKS5 proxy. Direct UDP connection is used if dialer is nil.
+func NewWithDialer(addr *net.UDPAddr, dialer DialFunc) (*Client, error) {
 	client, err := Create(addr)
 	if err != nil {
 		return nil, err
 	}
+	client.Dialer = dialer
 
 	if err := client.Dial(); err != nil {
 		return nil, err
@@ -50,18 +65,26 @@ func NewWithAddr(addr *net.UDPAddr) (*Client, error) {
 // Create creates a client without opening connection. Use Dial() to establish connection.
 func Create(addr *net.UDPAddr) (*Client, error) {
 	return &Client{
-		Address:    addr,
-		Timeout:    DefaultDeadlineTimeout * time.Second,
-		BufferSize: DefaultBufferSize,
-		readBuf:    make([]byte, DefaultBufferSize),
-		packetsBuf: make(map[int][]byte, 8),
-		parseData:  make([]byte, 0, 4096),
+		Address:         addr,
+		Timeout:         DefaultDeadlineTimeout * time.Second,
+		BufferSize:      DefaultBufferSize,
+		readBuf:         make([]byte, DefaultBufferSize),
+		FragmentTimeout: DefaultFragmentTimeout,
+		parseData:       make([]byte, 0, 4096),
 	}, nil
 }
 
-// Dial establishes UDP connection to the server.
+// Dial establishes UDP connection to the server, through Dialer if set.
 func (c *Client) Dial() error {
-	conn, err := net.DialUDP("udp", nil, c.Address)
+	var (
+		conn net.Conn
+		err  error
+	)
+	if c.Dialer != nil {
+		conn, err = c.Dialer(c.Address)
+	} else {
+		conn, err = net.DialUDP("udp", nil, c.Address)
+	}
 	if err != nil {
 		return err
 	}
@@ -85,7 +108,7 @@ func (c *Client) SetDeadlineTimeout(seconds int) {
 	c.Timeout = time.Duration(seconds) * time.Second
 }
 
-// Close closes UDP connection.
+// Close closes the connection.
 func (c *Client) Close() error {
 	return c.Conn.Close()
 }
@@ -129,7 +152,12 @@ func (c *Client) Get(requestType Flag) ([]byte, Flag, time.Duration, error) {
 }
 
 // request creates header, sends request and returns response with ping duration.
-// Handles multi-packet responses by collecting and assembling packets.
+// Handles multi-packet responses by collecting and assembling packets: fragments of other split IDs
+// are buffered apart, fragments with out of range numbers are dropped and every next fragment
+// must arrive within FragmentTimeout in addition to the overall Timeout.
+// Late fragments of split IDs left incomplete by previous queries are dropped without
+// shortening the deadline. A single packet of the expected type is returned even if
+// fragments were already collected.
 func (c *Client) request(requestType Flag, challenge uint32) ([]byte, time.Duration, error) {
 	req, err := createHeader(requestType, challenge)
 	if err != nil {
@@ -141,120 +169,123 @@ func (c *Client) request(requestType Flag, challenge uint32) ([]byte, time.Durat
 	if _, err := c.Conn.Write(req); err != nil {
 		return nil, 0, err
 	}
-	if err := c.Conn.SetReadDeadline(time.Now().Add(c.Timeout)); err != nil {
+	deadline := time.Now().Add(c.Timeout)
+	if err := c.Conn.SetReadDeadline(deadline); err != nil {
 		return nil, 0, err
 	}
 
 	var (
-		resp []byte
-		n    int
+		splits    *splitCollector
+		duration  time.Duration
+		truncated int
 	)
-	for attempt := 0; attempt < 3; attempt++ {
+	defer func() { c.keepStaleSplits(splits) }()
+
+	for {
 		if cap(c.readBuf) < int(c.BufferSize) {
 			c.readBuf = make([]byte, c.BufferSize)
 		}
-		resp = c.readBuf[:c.BufferSize]
-		n, err = c.Conn.Read(resp)
+		resp := c.readBuf[:c.BufferSize]
+		n, err := c.Conn.Read(resp)
 		if err != nil {
 			return nil, 0, err
 		}
+		if splits == nil {
+			duration = time.Since(start)
+		}
 
 		multi, err := isMultiPacket(resp[:n])
-		if err != nil && errors.Is(err, ErrMultiPacket) && multi {
-			continue // Some servers send a truncated split packet first; read again.
+		if err != nil {
+			if errors.Is(err, ErrMultiPacket) && multi && (splits != nil || truncated < 2) {
+				truncated++
+				continue // Some servers send a truncated split packet first; read again.
+			}
+			result := make([]byte, n)
+			copy(result, resp[:n])
+			return result, 0, err
 		}
-		break
-	}
 
-	duration := time.Since(start)
+		if !multi {
+			if splits != nil {
+				// Fragments collected so far may be stale leftovers of a previous query,
+				// a single packet answering this request wins over them.
+				if n < 5 || !expectedResponse(requestType, Flag(resp[4])) {
+					continue // Late single packet of a previous query while collecting fragments.
+				}
+				duration = time.Since(start)
+			}
+			result := make([]byte, n)
+			copy(result, resp[:n])
+			return result, duration, nil
+		}
 
-	multi, err := isMultiPacket(resp[:n])
-	if err != nil {
-		result := make([]byte, n)
-		copy(result, resp[:n])
-		return result, 0, err
-	}
+		if c.isStaleSplit(resp[:n]) {
+			continue // Late fragment of a previous query, the deadline is not shortened by it.
+		}
 
-	if !multi {
-		result := make([]byte, n)
-		copy(result, resp[:n])
-		return result, duration, nil
-	}
+		if splits == nil {
+			splits = newSplitCollector(c.Limits)
+		}
+		assembled, done, err := splits.add(resp[:n])
+		switch {
+		case errors.Is(err, ErrMultiPacketIndex):
+			continue // Corrupted or foreign fragment, wait for the valid one.
+		case err != nil:
+			return nil, 0, err
+		case done:
+			return assembled, duration, nil
+		}
 
-	// Multi-packet response: extract metadata from first packet
-	info, err := parseSplitHeader(resp[:n])
-	if err != nil {
-		return nil, 0, err
+		if err := c.fragmentDeadline(deadline); err != nil {
+			return nil, 0, err
+		}
 	}
+}
 
-	for k := range c.packetsBuf {
-		delete(c.packetsBuf, k)
-	}
-	if info.count > 8 && len(c.packetsBuf) == 0 {
-		c.packetsBuf = make(map[int][]byte, info.count)
+// fragmentDeadline shortens the read deadline to FragmentTimeout after a fragment,
+// but not beyond the overall deadline.
+func (c *Client) fragmentDeadline(deadline time.Time) error {
+	if c.FragmentTimeout <= 0 {
+		return nil
 	}
 
-	packets := c.packetsBuf
-	if n < info.dataOff {
-		return nil, 0, ErrMultiPacket
+	next := time.Now().Add(c.FragmentTimeout)
+	if next.After(deadline) {
+		next = deadline
 	}
-	firstPacketData := make([]byte, n-info.dataOff)
-	copy(firstPacketData, resp[info.dataOff:n])
-	packets[info.index] = firstPacketData
-
-	// Collect remaining packets
-	for len(packets) < info.count {
-		if cap(c.readBuf) < int(c.BufferSize) {
-			c.readBuf = make([]byte, c.BufferSize)
-		}
-
-		resp = c.readBuf[:c.BufferSize]
-		n, err := c.Conn.Read(resp)
-		if err != nil {
-			return nil, 0, err
-		}
 
-		if binary.LittleEndian.Uint32(resp[4:8]) != info.id {
-			return nil, 0, ErrMultiPacketInvalid
-		}
+	return c.Conn.SetReadDeadline(next)
+}
 
-		currentPacket := info.readPacketNumber(resp[:n])
-		if _, exists := packets[currentPacket]; !exists {
-			if n < info.headerSize {
-				return nil, 0, ErrMultiPacket
-			}
-			packetData := make([]byte, n-info.headerSize)
-			copy(packetData, resp[info.headerSize:n])
-			packets[currentPacket] = packetData
-		}
+// isStaleSplit reports whether the packet is a fragment of a split ID left incomplete by a previous query.
+func (c *Client) isStaleSplit(packet []byte) bool {
+	if len(c.staleSplits) == 0 {
+		return false
 	}
 
-	// Calculate total size and assemble packets in order
-	totalSize := 0
-	for i := 0; i < info.count; i++ {
-		if data, exists := packets[i]; exists {
-			totalSize += len(data)
-		} else {
-			return nil, 0, ErrMultiPacketMismatch
-		}
+	info, err := parseSplitHeader(packet)
+	return err == nil && slices.Contains(c.staleSplits, info.id)
+}
+
+// keepStaleSplits remembers split IDs the collector did not complete, the oldest are forgotten
+// beyond maxStaleSplits.
+func (c *Client) keepStaleSplits(splits *splitCollector) {
+	if splits == nil || len(splits.order) == 0 {
+		return
 	}
 
-	assembledResp := make([]byte, 0, totalSize)
-	for i := 0; i < info.count; i++ {
-		if data, exists := packets[i]; exists {
-			assembledResp = append(assembledResp, data...)
-		} else {
-			return nil, 0, ErrMultiPacketMismatch
-		}
+	c.staleSplits = append(c.staleSplits, splits.order...)
+	if extra := len(c.staleSplits) - maxStaleSplits; extra > 0 {
+		c.staleSplits = append(c.staleSplits[:0], c.staleSplits[extra:]...)
 	}
+}
 
-	if info.compressed {
-		decompressed, err := decompressBzip2(assembledResp, info.unpackedSize, info.crc)
-		if err != nil {
-			return nil, 0, err
-		}
-		return decompressed, duration, nil
+// expectedResponse reports whether the response type answers the request,
+// including challenges and A2S_INFO responses some servers return for A2S_RULES.
+func expectedResponse(request, response Flag) bool {
+	if response == challengeResponse || validateResponseType(request, response) == nil {
+		return true
 	}
 
-	return assembledResp, duration, nil
+	return request == RulesRequest && (response == infoResponseSource || response == infoResponseGoldSource)
 }
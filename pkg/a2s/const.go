@@ -5,9 +5,11 @@ import (
 )
 
 const (
-	DefaultDeadlineTimeout time.Duration = 5    // Default deadline timeout in seconds
-	DefaultBufferSize      uint16        = 4096 // conservative default to avoid UDP truncation
+	DefaultDeadlineTimeout time.Duration = 5           // Default deadline timeout in seconds
+	DefaultBufferSize      uint16        = 4096        // conservative default to avoid UDP truncation
+	DefaultFragmentTimeout time.Duration = time.Second // Default inactivity timeout between split fragments
 
+	NoChallenge  uint32 = 0xFFFFFFFF // Challenge value of requests sent without challenge
 	singlePacket uint32 = 0xFFFFFFFF // A2S single-packet header
 	multiPacket  uint32 = 0xFFFFFFFE // A2S multi-packet header
 
@@ -8,11 +8,10 @@ import (
 	"io"
 )
 
-const maxDecompressedSize = 16 * 1024 * 1024
-
-func decompressBzip2(compressed []byte, size uint32, crc uint32) ([]byte, error) {
-	if size > maxDecompressedSize {
-		return nil, fmt.Errorf("%w: %d > %d", ErrDecompressSize, size, maxDecompressedSize)
+// decompressBzip2 decompresses a split response of size bytes with crc, up to maxSize bytes.
+func decompressBzip2(compressed []byte, size uint32, crc uint32, maxSize int) ([]byte, error) {
+	if uint64(size) > uint64(maxSize) {
+		return nil, fmt.Errorf("%w: %d > %d", ErrDecompressSize, size, maxSize)
 	}
 
 	reader := bzip2.NewReader(bytes.NewReader(compressed))
@@ -1,6 +1,10 @@
 package a2s
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/woozymasta/a2s/internal/bread"
+)
 
 var (
 	// A2S_INFO errors
@@ -52,6 +56,8 @@ var (
 	ErrPlayerDeaths   = errors.New("A2S_PLAYER: deaths read failed")
 	ErrPlayerMoney    = errors.New("A2S_PLAYER: money read failed")
 
+	ErrPlayerCountLimit = errors.New("A2S_PLAYER: player count exceeds limit")
+
 	// A2S_RULES errors
 
 	ErrRuleRead  = errors.New("A2S_RULES: failed to read")
@@ -59,6 +65,8 @@ var (
 	ErrRuleKey   = errors.New("A2S_RULES: key read failed")
 	ErrRuleValue = errors.New("A2S_RULES: value read failed")
 
+	ErrRuleCountLimit = errors.New("A2S_RULES: rule count exceeds limit")
+
 	// A2A_PING errors
 
 	ErrPingRead    = errors.New("A2S_PING: failed to read")
@@ -79,6 +87,9 @@ var (
 	ErrInsufficientData    = errors.New("insufficient data length")
 	ErrMultiPacketInvalid  = errors.New("received invalid packet identifier in response")
 	ErrMultiPacketMismatch = errors.New("mismatched number of packets received")
+	ErrMultiPacketIndex    = errors.New("received split packet number out of range")
+	ErrMultiPacketCount    = errors.New("split packet count exceeds limit")
+	ErrMultiPacketSize     = errors.New("split response size exceeds limit")
 
 	// Validator errors
 
@@ -96,4 +107,8 @@ var (
 	ErrDecompressFailed       = errors.New("bz2 decompression failed")
 	ErrDecompressSizeMismatch = errors.New("bz2 decompressed size mismatch")
 	ErrDecompressCRC          = errors.New("bz2 CRC32 checksum mismatch")
+
+	// Limit errors
+
+	ErrStringLength = bread.ErrStringLen
 )
@@ -4,6 +4,12 @@ import (
 	"encoding/binary"
 )
 
+// BuildRequest builds A2S request packet for sending over own sockets, e.g. broadcast.
+// Use NoChallenge for requests without challenge.
+func BuildRequest(requestType Flag, challenge uint32) ([]byte, error) {
+	return createHeader(requestType, challenge)
+}
+
 // createHeader builds A2S protocol request header.
 // InfoRequest includes "Source Engine Query" payload, other requests include challenge value.
 //   - InfoRequest      = 0x54
@@ -0,0 +1,78 @@
+package a2s
+
+import (
+	"fmt"
+
+	"github.com/woozymasta/a2s/internal/bread"
+)
+
+// Limits bounds allocations driven by counts and sizes the server sends,
+// e.g. for mass scanners querying untrusted responders. Zero fields use DefaultLimits.
+type Limits struct {
+	MaxSplitFragments   int // Max packets of a split response, ErrMultiPacketCount
+	MaxSplitIDs         int // Max split responses buffered at once, the oldest are dropped
+	MaxAssembledBytes   int // Max buffered bytes of split responses, ErrMultiPacketSize
+	MaxDecompressedSize int // Max bzip2 decompressed size, ErrDecompressSize
+	MaxPlayers          int // Max players in A2S_PLAYER, ErrPlayerCountLimit
+	MaxRules            int // Max rules in A2S_RULES, ErrRuleCountLimit
+	MaxMods             int // Max mods in A3SB rules, a3sb.ErrModCountLimit
+	MaxStringLength     int // Max length of a string field, ErrStringLength
+}
+
+// DefaultLimits are the limits used for zero Limits fields.
+var DefaultLimits = Limits{
+	MaxSplitFragments:   128,
+	MaxSplitIDs:         4,
+	MaxAssembledBytes:   1024 * 1024,
+	MaxDecompressedSize: 16 * 1024 * 1024,
+	MaxPlayers:          255,
+	MaxRules:            8192,
+	MaxMods:             255,
+	MaxStringLength:     4096,
+}
+
+// WithDefaults returns the limits with zero fields set from DefaultLimits.
+func (l Limits) WithDefaults() Limits {
+	set := func(value *int, fallback int) {
+		if *value <= 0 {
+			*value = fallback
+		}
+	}
+
+	set(&l.MaxSplitFragments, DefaultLimits.MaxSplitFragments)
+	set(&l.MaxSplitIDs, DefaultLimits.MaxSplitIDs)
+	set(&l.MaxAssembledBytes, DefaultLimits.MaxAssembledBytes)
+	set(&l.MaxDecompressedSize, DefaultLimits.MaxDecompressedSize)
+	set(&l.MaxPlayers, DefaultLimits.MaxPlayers)
+	set(&l.MaxRules, DefaultLimits.MaxRules)
+	set(&l.MaxMods, DefaultLimits.MaxMods)
+	set(&l.MaxStringLength, DefaultLimits.MaxStringLength)
+
+	return l
+}
+
+// CheckRules returns ErrRuleCountLimit if count exceeds MaxRules.
+func (l Limits) CheckRules(count int) error {
+	if limit := l.WithDefaults().MaxRules; count > limit {
+		return fmt.Errorf("%w: %d > %d", ErrRuleCountLimit, count, limit)
+	}
+
+	return nil
+}
+
+// checkPlayers returns ErrPlayerCountLimit if count exceeds MaxPlayers.
+func (l Limits) checkPlayers(count int) error {
+	if limit := l.WithDefaults().MaxPlayers; count > limit {
+		return fmt.Errorf("%w: %d > %d", ErrPlayerCountLimit, count, limit)
+	}
+
+	return nil
+}
+
+// reader returns a reader of data limiting string length to MaxStringLength.
+func (l Limits) reader(data []byte) *bread.Reader {
+	reader := bread.NewReader(data)
+	reader.SetMaxString(l.WithDefaults().MaxStringLength)
+
+	return reader
+}
@@ -0,0 +1,55 @@
+package a2s
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	limits := Limits{MaxPlayers: 1, MaxRules: 1, MaxStringLength: 8, MaxDecompressedSize: 64, MaxSplitFragments: 2, MaxAssembledBytes: 16}
+
+	players := []byte{2}
+	if _, err := parsePlayers(players, limits); !errors.Is(err, ErrPlayerCountLimit) {
+		t.Errorf("players: err = %v, want ErrPlayerCountLimit", err)
+	}
+
+	rules := binary.LittleEndian.AppendUint16(nil, 2)
+	if _, err := parseRules(rules, limits); !errors.Is(err, ErrRuleCountLimit) {
+		t.Errorf("rules: err = %v, want ErrRuleCountLimit", err)
+	}
+
+	rules = binary.LittleEndian.AppendUint16(nil, 1)
+	rules = append(rules, strings.Repeat("k", 9)+"\x00v\x00"...)
+	if _, err := parseRules(rules, limits); !errors.Is(err, ErrStringLength) {
+		t.Errorf("string: err = %v, want ErrStringLength", err)
+	}
+	if _, err := parseRules(rules, Limits{}); err != nil {
+		t.Errorf("default limits: %v", err)
+	}
+
+	if _, err := decompressBzip2(nil, 65, 0, limits.WithDefaults().MaxDecompressedSize); !errors.Is(err, ErrDecompressSize) {
+		t.Errorf("decompress: err = %v, want ErrDecompressSize", err)
+	}
+
+	single := newSplitCollector(Limits{MaxSplitIDs: 1})
+	for id := uint32(1); id <= 2; id++ {
+		_, _, _ = single.add(splitPacket(id, 2, 1, []byte("b")))
+	}
+	if len(single.responses) != 1 {
+		t.Errorf("split IDs: buffered %d responses, want 1", len(single.responses))
+	}
+
+	splits := newSplitCollector(limits)
+	if _, _, err := splits.add(splitPacket(1, 3, 0, nil)); !errors.Is(err, ErrMultiPacketCount) {
+		t.Errorf("fragments: err = %v, want ErrMultiPacketCount", err)
+	}
+	if _, _, err := splits.add(splitPacket(1, 2, 1, make([]byte, 17))); !errors.Is(err, ErrMultiPacketSize) {
+		t.Errorf("assembled: err = %v, want ErrMultiPacketSize", err)
+	}
+
+	if got := (Limits{MaxPlayers: 10}).WithDefaults(); got.MaxPlayers != 10 || got.MaxRules != DefaultLimits.MaxRules {
+		t.Errorf("WithDefaults() = %+v", got)
+	}
+}
@@ -0,0 +1,303 @@
+package a2s
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Response is a reply to a pipelined request.
+type Response struct {
+	Err     error         // Request error, e.g. timeout if the server did not reply
+	Data    []byte        // Response data without header and type byte
+	Ping    time.Duration // Time from the last send to the response
+	Request Flag          // Request type
+	Type    Flag          // Response type
+	limits  Limits        // Decoding limits of the client
+}
+
+// All contains A2S_INFO, A2S_PLAYER and A2S_RULES responses queried in one round trip.
+type All struct {
+	Info    *Info             `json:"info,omitempty"`    // A2S_INFO response
+	Rules   map[string]string `json:"rules,omitempty"`   // A2S_RULES response
+	Players []Player          `json:"players,omitempty"` // A2S_PLAYER response
+}
+
+// GetAll queries A2S_INFO, A2S_PLAYER and A2S_RULES in one round trip using GetPipelined.
+// Parts that were received and parsed are returned even if the error is not nil.
+func (c *Client) GetAll() (*All, error) {
+	responses, err := c.GetPipelined(InfoRequest, PlayerRequest, RulesRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	all := &All{}
+	var errs []error
+
+	if all.Info, err = responses[0].Info(); err != nil {
+		errs = append(errs, err)
+	}
+	if all.Players, err = responses[1].Players(); err != nil {
+		errs = append(errs, err)
+	}
+	if all.Rules, err = responses[2].Rules(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return all, errors.Join(errs...)
+}
+
+// Info parses the response as A2S_INFO.
+func (r Response) Info() (*Info, error) {
+	if r.Err != nil {
+		return nil, errors.Join(ErrInfoRead, r.Err)
+	}
+	if err := validateResponseType(InfoRequest, r.Type); err != nil {
+		return nil, err
+	}
+
+	return parseInfo(r.Data, r.Type, r.Ping, r.limits)
+}
+
+// Players parses the response as A2S_PLAYER.
+func (r Response) Players() ([]Player, error) {
+	if r.Err != nil {
+		return nil, errors.Join(ErrPlayerRead, r.Err)
+	}
+	if err := validateResponseType(PlayerRequest, r.Type); err != nil {
+		return nil, err
+	}
+
+	return parsePlayers(r.Data, r.limits)
+}
+
+// Rules parses the response as standard A2S_RULES.
+func (r Response) Rules() (map[string]string, error) {
+	if r.Err != nil {
+		return nil, errors.Join(ErrRuleRead, r.Err)
+	}
+	if err := validateResponseType(RulesRequest, r.Type); err != nil {
+		return nil, err
+	}
+
+	return parseRules(r.Data, r.limits)
+}
+
+// GetPipelined sends A2S_INFO, A2S_PLAYER and A2S_RULES requests together on one socket
+// and demultiplexes replies by response type. A single challenge is shared by all requests:
+// on the first challenge response every pending request is resent with it.
+// Split responses follow the same FragmentTimeout and Limits as Get: a response exceeding limits
+// fails the affected request with its limit error, a server asking for a third new challenge fails
+// all pending requests with the validation error. A2S_RULES answered with A2S_INFO after the info
+// was received is resent up to twice, as Get does.
+// Responses are returned in the order of requests, per-request errors are stored in Response.Err.
+func (c *Client) GetPipelined(requests ...Flag) ([]Response, error) {
+	responses := make([]Response, len(requests))
+	pending := make(map[Flag]int, len(requests))
+
+	for i, request := range requests {
+		switch request {
+		case InfoRequest, PlayerRequest, RulesRequest:
+		default:
+			return nil, errors.Join(ErrWrongRequest, fmt.Errorf("0x%X", request))
+		}
+		if _, exists := pending[request]; exists {
+			return nil, errors.Join(ErrWrongRequest, fmt.Errorf("duplicate 0x%X", request))
+		}
+
+		pending[request] = i
+		responses[i].Request = request
+		responses[i].limits = c.Limits
+	}
+
+	challenge := singlePacket
+	start, err := c.sendPending(pending, challenge)
+	if err != nil {
+		return nil, err
+	}
+	deadline := start.Add(c.Timeout)
+
+	splits := newSplitCollector(c.Limits)
+	defer c.keepStaleSplits(splits)
+	challenges, rulesRetries := 0, 0
+
+	for len(pending) > 0 {
+		if cap(c.readBuf) < int(c.BufferSize) {
+			c.readBuf = make([]byte, c.BufferSize)
+		}
+
+		n, err := c.Conn.Read(c.readBuf[:c.BufferSize])
+		if err != nil {
+			for _, i := range pending {
+				responses[i].Err = err
+			}
+			break
+		}
+		packet := c.readBuf[:n]
+
+		multi, err := isMultiPacket(packet)
+		if err != nil {
+			continue // Truncated or foreign packet, wait for the next one.
+		}
+		if multi {
+			if c.isStaleSplit(packet) {
+				continue // Late fragment of a previous query, the deadline is not shortened by it.
+			}
+
+			assembled, done, err := splits.add(packet)
+			switch {
+			case errors.Is(err, ErrMultiPacketIndex), errors.Is(err, ErrMultiPacket):
+				continue // Corrupted or foreign fragment, wait for the valid one.
+			case err != nil:
+				request := splitRequest(pending)
+				responses[pending[request]].Err = err
+				delete(pending, request)
+				continue
+			case !done:
+				if err := c.fragmentDeadline(deadline); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			packet = assembled
+			if err := c.Conn.SetReadDeadline(deadline); err != nil {
+				return nil, err
+			}
+		}
+		if len(packet) < 5 {
+			continue
+		}
+
+		flag := Flag(packet[4])
+		data := packet[5:]
+
+		if flag == challengeResponse {
+			if len(data) < 4 {
+				continue
+			}
+
+			value := binary.BigEndian.Uint32(data[:4])
+			if value == challenge {
+				continue // Challenge for a request already resent with it.
+			}
+			if challenges >= 2 {
+				// Server keeps asking for a new challenge, fail like Get does.
+				for request, i := range pending {
+					responses[i].Err = validateResponseType(request, flag)
+				}
+				break
+			}
+
+			challenge = value
+			challenges++
+			if start, err = c.sendPending(pending, challenge); err != nil {
+				return nil, err
+			}
+			deadline = start.Add(c.Timeout)
+			continue
+		}
+
+		request, ok := requestForResponse(flag)
+		if !ok {
+			continue
+		}
+		i, ok := pending[request]
+		if !ok {
+			rules, rulesPending := pending[RulesRequest]
+			if request != InfoRequest || !rulesPending {
+				continue // Duplicate or late response.
+			}
+
+			// Some servers answer A2S_RULES with A2S_INFO, ask for the rules again.
+			if rulesRetries >= 2 {
+				responses[rules].Err = validateResponseType(RulesRequest, flag)
+				delete(pending, RulesRequest)
+				continue
+			}
+			rulesRetries++
+			if deadline, err = c.resend(RulesRequest, challenge); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		result := make([]byte, len(data))
+		copy(result, data)
+		responses[i].Data = result
+		responses[i].Type = flag
+		responses[i].Ping = time.Since(start)
+		delete(pending, request)
+	}
+
+	return responses, nil
+}
+
+// splitRequest returns the pending request a failed split response is attributed to.
+// The response type is unknown until the fragments are assembled, A2S_RULES is the
+// usual split response, followed by A2S_PLAYER on crowded servers.
+func splitRequest(pending map[Flag]int) Flag {
+	for _, request := range []Flag{RulesRequest, PlayerRequest, InfoRequest} {
+		if _, ok := pending[request]; ok {
+			return request
+		}
+	}
+
+	return RulesRequest
+}
+
+// sendPending writes all pending requests with challenge and resets the read deadline.
+// Returns the time of sending.
+func (c *Client) sendPending(pending map[Flag]int, challenge uint32) (time.Time, error) {
+	// Send in fixed order to keep request sequence stable for servers and tests
+	for _, request := range []Flag{InfoRequest, PlayerRequest, RulesRequest} {
+		if _, ok := pending[request]; !ok {
+			continue
+		}
+
+		req, err := createHeader(request, challenge)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if _, err := c.Conn.Write(req); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	start := time.Now()
+	if err := c.Conn.SetReadDeadline(start.Add(c.Timeout)); err != nil {
+		return time.Time{}, err
+	}
+
+	return start, nil
+}
+
+// resend writes the request with challenge again and resets the read deadline.
+// Returns the new deadline.
+func (c *Client) resend(request Flag, challenge uint32) (time.Time, error) {
+	req, err := createHeader(request, challenge)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if _, err := c.Conn.Write(req); err != nil {
+		return time.Time{}, err
+	}
+
+	deadline := time.Now().Add(c.Timeout)
+	return deadline, c.Conn.SetReadDeadline(deadline)
+}
+
+// requestForResponse returns the request type answered by the response type.
+func requestForResponse(response Flag) (Flag, bool) {
+	switch response {
+	case infoResponseSource, infoResponseGoldSource:
+		return InfoRequest, true
+	case playerResponse:
+		return PlayerRequest, true
+	case rulesResponse:
+		return RulesRequest, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,135 @@
+package a2s
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testChallenge uint32 = 0x11223344
+
+// pipelineServer is a local UDP server requiring a challenge and answering rules with a split response.
+func pipelineServer(t *testing.T, requests *atomic.Int32) *net.UDPAddr {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	info := []byte{0xFF, 0xFF, 0xFF, 0xFF, byte(infoResponseSource), 17}
+	info = append(info, "Pipeline\x00map\x00folder\x00Game\x00"...)
+	info = binary.LittleEndian.AppendUint16(info, 440)
+	info = append(info, 1, 32, 0, 'd', 'l', 0, 0)
+	info = append(info, "1.0\x00"...)
+	info = append(info, 0)
+
+	players := []byte{0xFF, 0xFF, 0xFF, 0xFF, byte(playerResponse), 1, 0}
+	players = append(players, "Alice\x00"...)
+	players = binary.LittleEndian.AppendUint32(players, 7)
+	players = binary.LittleEndian.AppendUint32(players, math.Float32bits(60))
+
+	rules := []byte{0xFF, 0xFF, 0xFF, 0xFF, byte(rulesResponse)}
+	rules = binary.LittleEndian.AppendUint16(rules, 2)
+	rules = append(rules, "mp_timelimit\x0030\x00sv_tags\x00alltalk\x00"...)
+
+	split := func(index int, part []byte) []byte {
+		packet := binary.LittleEndian.AppendUint32(nil, multiPacket)
+		packet = binary.LittleEndian.AppendUint32(packet, 7)
+		packet = append(packet, 2, byte(index))
+		packet = binary.LittleEndian.AppendUint16(packet, 1248)
+		return append(packet, part...)
+	}
+
+	go func() {
+		buf := make([]byte, 1400)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			requests.Add(1)
+
+			req := buf[:n]
+			if len(req) < 9 || binary.BigEndian.Uint32(req[n-4:]) != testChallenge {
+				reply := []byte{0xFF, 0xFF, 0xFF, 0xFF, byte(challengeResponse)}
+				_, _ = conn.WriteToUDP(binary.BigEndian.AppendUint32(reply, testChallenge), addr)
+				continue
+			}
+
+			switch Flag(req[4]) {
+			case InfoRequest:
+				_, _ = conn.WriteToUDP(info, addr)
+			case PlayerRequest:
+				_, _ = conn.WriteToUDP(players, addr)
+			case RulesRequest:
+				// second part first to check reassembly of out of order packets
+				_, _ = conn.WriteToUDP(split(1, rules[20:]), addr)
+				_, _ = conn.WriteToUDP(split(0, rules[:20]), addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+func TestGetAll(t *testing.T) {
+	var requests atomic.Int32
+	client, err := NewWithAddr(pipelineServer(t, &requests))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	client.Timeout = 2 * time.Second
+
+	all, err := client.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if all.Info == nil || all.Info.Name != "Pipeline" || all.Info.ID != 440 {
+		t.Errorf("Info = %+v", all.Info)
+	}
+	if len(all.Players) != 1 || all.Players[0].Name != "Alice" || all.Players[0].Duration != time.Minute {
+		t.Errorf("Players = %+v", all.Players)
+	}
+	if all.Rules["mp_timelimit"] != "30" || all.Rules["sv_tags"] != "alltalk" {
+		t.Errorf("Rules = %v", all.Rules)
+	}
+	if got := requests.Load(); got != 6 {
+		t.Errorf("server received %d requests, want 6 (3 initial and 3 with shared challenge)", got)
+	}
+}
+
+func TestGetPipelinedTimeout(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	client, err := NewWithAddr(conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	client.Timeout = 100 * time.Millisecond
+
+	responses, err := client.GetPipelined(InfoRequest, RulesRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range responses {
+		if r.Err == nil {
+			t.Errorf("request 0x%X: expected timeout error", r.Request)
+		}
+	}
+
+	if _, err := client.GetPipelined(InfoRequest, InfoRequest); err == nil {
+		t.Error("expected error for duplicate requests")
+	}
+}
@@ -1,6 +1,9 @@
 package a2s
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+	"fmt"
+)
 
 const (
 	splitMin       = 9                // Minimum size of a split header.
@@ -10,6 +13,7 @@ const (
 	splitSizeOff   = 10               // Offset of the split size in a Source split header.
 	splitSizeMax   = 4096             // Max allowed split size.
 	unpackProbeMax = 32 * 1024 * 1024 // Max allowed decompressed size for probe.
+	maxStaleSplits = 16               // Max split IDs of previous queries remembered as stale.
 )
 
 // splitHeaderInfo contains metadata about a split packet.
@@ -78,8 +82,8 @@ func parseSplitHeader(data []byte) (splitHeaderInfo, error) {
 		goldSrc:    useGold,
 	}
 
-	// Check if packet is compressed and set decompressed size and CRC.
-	if (packetID & 0x80000000) != 0 {
+	// Check if packet is compressed and set decompressed size and CRC, only packet 0 carries them.
+	if (packetID&0x80000000) != 0 && currentPacket == 0 {
 		if len(data) < baseHeaderSize+8 {
 			return splitHeaderInfo{}, ErrMultiPacket
 		}
@@ -109,12 +113,117 @@ func parseSplitHeader(data []byte) (splitHeaderInfo, error) {
 	return info, nil
 }
 
-// readPacketNumber reads the packet number from a packet.
-// Returns the packet number.
-func (s splitHeaderInfo) readPacketNumber(data []byte) int {
-	if s.goldSrc {
-		return int((data[8] & 0xF0) >> 4)
+// splitCollector reassembles interleaved multi-packet responses by packet ID.
+// Fragments of stale IDs, e.g. late fragments of a previous timed out query, are buffered apart
+// and evicted oldest first, so they neither break nor outgrow the current response.
+type splitCollector struct {
+	responses map[uint32]*splitResponse // Partially received responses by packet ID
+	order     []uint32                  // Packet IDs in order of their first fragment
+	size      int                       // Buffered payload bytes of all responses
+	limits    Limits                    // Fragment count, buffered and decompressed size limits
+}
+
+// splitResponse is a partially received multi-packet response.
+type splitResponse struct {
+	parts map[int][]byte  // Packet payloads by packet number
+	first splitHeaderInfo // Header of packet 0, carries compression info
+	count int             // Total number of packets
+	size  int             // Buffered payload bytes
+}
+
+// newSplitCollector creates an empty split collector bounded by limits.
+func newSplitCollector(limits Limits) *splitCollector {
+	return &splitCollector{responses: make(map[uint32]*splitResponse, 2), limits: limits.WithDefaults()}
+}
+
+// add stores a split packet and returns the assembled response once all packets of its ID are received.
+// Packets with out of range numbers are rejected with ErrMultiPacketIndex, responses with more than
+// MaxSplitFragments packets with ErrMultiPacketCount and exceeding MaxAssembledBytes with ErrMultiPacketSize.
+func (s *splitCollector) add(packet []byte) ([]byte, bool, error) {
+	info, err := parseSplitHeader(packet)
+	if err != nil {
+		return nil, false, err
+	}
+	if info.count > s.limits.MaxSplitFragments {
+		return nil, false, fmt.Errorf("%w: %d > %d", ErrMultiPacketCount, info.count, s.limits.MaxSplitFragments)
+	}
+	if info.index < 0 || info.index >= info.count {
+		return nil, false, fmt.Errorf("%w: %d of %d", ErrMultiPacketIndex, info.index, info.count)
+	}
+
+	response, ok := s.responses[info.id]
+	if !ok {
+		if len(s.order) >= s.limits.MaxSplitIDs {
+			s.evict(s.order[0])
+		}
+		response = &splitResponse{parts: make(map[int][]byte, info.count), count: info.count}
+		s.responses[info.id] = response
+		s.order = append(s.order, info.id)
+	}
+	if info.count != response.count {
+		return nil, false, fmt.Errorf("%w: %d of %d", ErrMultiPacketIndex, info.index, response.count)
+	}
+
+	offset := info.headerSize
+	if info.index == 0 {
+		response.first = info
+		offset = info.dataOff
+	}
+	if len(packet) < offset {
+		return nil, false, ErrMultiPacket
+	}
+	if _, exists := response.parts[info.index]; !exists {
+		if s.size+len(packet)-offset > s.limits.MaxAssembledBytes {
+			return nil, false, fmt.Errorf("%w: %d bytes", ErrMultiPacketSize, s.limits.MaxAssembledBytes)
+		}
+
+		part := make([]byte, len(packet)-offset)
+		copy(part, packet[offset:])
+		response.parts[info.index] = part
+		response.size += len(part)
+		s.size += len(part)
+	}
+
+	if len(response.parts) < response.count {
+		return nil, false, nil
+	}
+	s.evict(info.id)
+
+	totalSize := 0
+	for i := 0; i < response.count; i++ {
+		data, exists := response.parts[i]
+		if !exists {
+			return nil, false, ErrMultiPacketMismatch
+		}
+		totalSize += len(data)
+	}
+
+	assembled := make([]byte, 0, totalSize)
+	for i := 0; i < response.count; i++ {
+		assembled = append(assembled, response.parts[i]...)
 	}
 
-	return int(data[9])
+	if response.first.compressed {
+		decompressed, err := decompressBzip2(assembled, response.first.unpackedSize, response.first.crc, s.limits.MaxDecompressedSize)
+		if err != nil {
+			return nil, false, err
+		}
+		return decompressed, true, nil
+	}
+
+	return assembled, true, nil
+}
+
+// evict drops the partially received response of the packet ID.
+func (s *splitCollector) evict(id uint32) {
+	if response, ok := s.responses[id]; ok {
+		s.size -= response.size
+		delete(s.responses, id)
+	}
+	for i, candidate := range s.order {
+		if candidate == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
 }
@@ -0,0 +1,57 @@
+package a2s
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// splitPacket builds a Source split packet.
+func splitPacket(id uint32, count, index int, payload []byte) []byte {
+	packet := binary.LittleEndian.AppendUint32(nil, multiPacket)
+	packet = binary.LittleEndian.AppendUint32(packet, id)
+	packet = append(packet, byte(count), byte(index))
+	packet = binary.LittleEndian.AppendUint16(packet, 1248)
+
+	return append(packet, payload...)
+}
+
+func TestSplitCollectorLimits(t *testing.T) {
+	splits := newSplitCollector(Limits{})
+
+	if _, _, err := splits.add(splitPacket(1, 2, 2, []byte("x"))); !errors.Is(err, ErrMultiPacketIndex) {
+		t.Errorf("index beyond count: err = %v, want ErrMultiPacketIndex", err)
+	}
+	if _, _, err := splits.add(splitPacket(1, DefaultLimits.MaxSplitFragments+1, 0, []byte("x"))); !errors.Is(err, ErrMultiPacketCount) {
+		t.Errorf("count beyond limit: err = %v, want ErrMultiPacketCount", err)
+	}
+
+	// Stale IDs are evicted oldest first and the oldest can no longer complete
+	maxSplitIDs := DefaultLimits.MaxSplitIDs
+	for id := uint32(1); id <= uint32(maxSplitIDs)+1; id++ {
+		if _, done, err := splits.add(splitPacket(id, 2, 1, []byte("b"))); err != nil || done {
+			t.Fatalf("add id %d: done %v, err %v", id, done, err)
+		}
+	}
+	if len(splits.responses) != maxSplitIDs || splits.size != maxSplitIDs {
+		t.Errorf("buffered %d responses of %d bytes, want %d", len(splits.responses), splits.size, maxSplitIDs)
+	}
+	if _, done, _ := splits.add(splitPacket(1, 2, 0, []byte("a"))); done {
+		t.Error("evicted response completed")
+	}
+
+	data, done, err := splits.add(splitPacket(uint32(maxSplitIDs)+1, 2, 0, []byte("a")))
+	if err != nil || !done || string(data) != "ab" {
+		t.Errorf("add = %q, %v, %v", data, done, err)
+	}
+
+	big := make([]byte, DefaultLimits.MaxAssembledBytes/4)
+	splits = newSplitCollector(Limits{})
+	var sizeErr error
+	for i := 0; i < 5 && sizeErr == nil; i++ {
+		_, _, sizeErr = splits.add(splitPacket(7, 8, i, big))
+	}
+	if !errors.Is(sizeErr, ErrMultiPacketSize) {
+		t.Errorf("oversized response: err = %v, want ErrMultiPacketSize", sizeErr)
+	}
+}
@@ -58,11 +58,14 @@ func (c *Client) GetTheShipPlayers() (*[]TheShipPlayer, error) {
 	c.parseData = c.parseData[:len(data)]
 	copy(c.parseData, data)
 
-	reader := bread.NewReader(c.parseData)
+	reader := c.Limits.reader(c.parseData)
 	count, err := reader.Byte()
 	if err != nil {
 		return nil, errors.Join(ErrPlayerCount, err)
 	}
+	if err := c.Limits.checkPlayers(int(count)); err != nil {
+		return nil, err
+	}
 
 	players := make([]TheShipPlayer, 0, int(count))
 
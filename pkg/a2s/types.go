@@ -2,6 +2,7 @@ package a2s
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // Flag represents request/response type byte in A2S protocol header.
@@ -29,6 +30,16 @@ func (i InfoFormat) MarshalJSON() ([]byte, error) {
 	return json.Marshal(i.String())
 }
 
+// UnmarshalJSON restores InfoFormat from JSON string.
+func (i *InfoFormat) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, map[string]InfoFormat{
+		"Source":     InfoFormat(infoResponseSource),
+		"GoldSource": InfoFormat(infoResponseGoldSource),
+	})
+	*i = value
+	return err
+}
+
 // ServerType represents the bytes for server type: Dedicated, Local or Proxy (SteamTV/HLTV) in A2S_INFO response
 type ServerType byte
 
@@ -50,6 +61,13 @@ func (s ServerType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.String())
 }
 
+// UnmarshalJSON restores ServerType from JSON string.
+func (s *ServerType) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, map[string]ServerType{"Dedicated": 'd', "Local": 'l', "Proxy": 'p'})
+	*s = value
+	return err
+}
+
 // Environment represents server operating system in A2S_INFO response.
 type Environment byte
 
@@ -73,6 +91,13 @@ func (e Environment) MarshalJSON() ([]byte, error) {
 	return json.Marshal(e.String())
 }
 
+// UnmarshalJSON restores Environment from JSON string.
+func (e *Environment) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, map[string]Environment{"Linux": 'l', "Windows": 'w', "Mac": 'm', "Other": 'o'})
+	*e = value
+	return err
+}
+
 // TheShipMode represents game mode for The Ship game in A2S_INFO response.
 type TheShipMode byte
 
@@ -99,3 +124,27 @@ func (m TheShipMode) String() string {
 func (m TheShipMode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m.String())
 }
+
+// UnmarshalJSON restores TheShipMode from JSON string.
+func (m *TheShipMode) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, map[string]TheShipMode{
+		"Hunt": 0, "Elimination": 1, "Duel": 2, "Deathmatch": 3, "VIP Team": 4, "Team Elimination": 5,
+	})
+	*m = value
+	return err
+}
+
+// unmarshalEnum decodes a JSON string written by MarshalJSON of byte enums.
+// Unknown names decode to zero, plain JSON numbers are accepted as raw values.
+func unmarshalEnum[T ~byte](data []byte, values map[string]T) (T, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var raw byte
+		if errNum := json.Unmarshal(data, &raw); errNum != nil {
+			return 0, fmt.Errorf("unexpected enum value %s: %w", data, err)
+		}
+		return T(raw), nil
+	}
+
+	return values[name], nil
+}
@@ -0,0 +1,224 @@
+package a2stest
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+// getInfo queries A2S_INFO and checks the server name.
+func getInfo(c *a2s.Client) error {
+	info, err := c.GetInfo()
+	if err != nil {
+		return err
+	}
+	if info.Name != ServerName || info.ID != uint64(AppID) {
+		return errors.New("unexpected info " + info.Name)
+	}
+
+	return nil
+}
+
+// getRules queries A2S_RULES and checks the rules.
+func getRules(c *a2s.Client) error {
+	rules, err := c.GetRules()
+	if err != nil {
+		return err
+	}
+	if !maps.Equal(rules, Rules) {
+		return errors.New("unexpected rules")
+	}
+
+	return nil
+}
+
+// getPlayers queries A2S_PLAYER.
+func getPlayers(c *a2s.Client) error {
+	_, err := c.GetPlayers()
+	return err
+}
+
+func TestScenarios(t *testing.T) {
+	tests := []struct {
+		want            error
+		script          Script
+		query           func(*a2s.Client) error
+		name            string
+		timeout         time.Duration
+		fragmentTimeout time.Duration
+		requests        int
+	}{
+		{name: "normal info", script: Normal(), query: getInfo, requests: 1},
+		{name: "normal rules", script: Normal(), query: getRules, requests: 1},
+		{name: "challenge", script: WithChallenge(0x0BADC0DE, Normal()), query: getRules, requests: 2},
+		{name: "truncated first packet", script: TruncatedFirstPacket(), query: getInfo, requests: 1},
+		{name: "out of order fragments", script: OutOfOrderFragments(), query: getRules, requests: 1},
+		{name: "duplicated fragments", script: DuplicatedFragments(), query: getRules, requests: 1},
+		{name: "interleaved split IDs", script: InterleavedSplitIDs(), query: getRules, requests: 1},
+		{name: "stale fragment info", script: StaleFragment(), query: getInfo, requests: 1},
+		{name: "stale fragment rules", script: StaleFragment(), query: getRules, requests: 1},
+		{name: "out of range fragment", script: OutOfRangeFragment(), query: getRules, requests: 1},
+		{name: "stalled fragments", script: StalledFragments(), query: getRules, want: os.ErrDeadlineExceeded, timeout: time.Minute, fragmentTimeout: 100 * time.Millisecond},
+		{name: "endless challenge info", script: EndlessChallenge(), query: getInfo, want: a2s.ErrValidatorInfo, requests: 3},
+		{name: "endless challenge rules", script: EndlessChallenge(), query: getRules, want: a2s.ErrValidatorRules, requests: 9},
+		{name: "wrong type info", script: WrongResponseType(), query: getInfo, want: a2s.ErrValidatorInfo},
+		{name: "wrong type players", script: WrongResponseType(), query: getPlayers, want: a2s.ErrValidatorPlayer},
+		{name: "wrong type rules", script: WrongResponseType(), query: getRules, want: a2s.ErrValidatorRules, requests: 3},
+		{name: "info for rules", script: InfoForRules(), query: getRules, requests: 2},
+		{name: "compressed", script: Compressed(), query: getRules, requests: 1},
+		{name: "corrupted CRC", script: CorruptedCRC(), query: getRules, want: a2s.ErrDecompressCRC},
+		{name: "slow within timeout", script: Slow(50 * time.Millisecond), query: getInfo, timeout: time.Second},
+		{name: "slow beyond timeout", script: Slow(time.Second), query: getInfo, want: os.ErrDeadlineExceeded},
+		{name: "silence", script: Silence(), query: getInfo, want: os.ErrDeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewServer(tt.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = server.Close() }()
+
+			client, err := a2s.NewWithAddr(server.Addr())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = client.Close() }()
+
+			client.Timeout = 200 * time.Millisecond
+			if tt.timeout > 0 {
+				client.Timeout = tt.timeout
+			}
+			if tt.fragmentTimeout > 0 {
+				client.FragmentTimeout = tt.fragmentTimeout
+			}
+
+			err = tt.query(client)
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+
+			if tt.requests > 0 && server.Requests() != tt.requests {
+				t.Errorf("server received %d requests, want %d", server.Requests(), tt.requests)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	response := RulesResponse()
+	fragments := Split(1, response, 50)
+	if len(fragments) != 3 {
+		t.Fatalf("got %d fragments, want 3", len(fragments))
+	}
+
+	var joined []byte
+	for i, fragment := range fragments {
+		if int(fragment[8]) != 3 || int(fragment[9]) != i {
+			t.Errorf("fragment %d header count %d index %d", i, fragment[8], fragment[9])
+		}
+		joined = append(joined, fragment[12:]...)
+	}
+	if string(joined) != string(response) {
+		t.Error("joined fragments differ from response")
+	}
+}
+
+func TestLateFragment(t *testing.T) {
+	server, err := NewServer(LateFragment(300 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }()
+
+	client, err := a2s.NewWithAddr(server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	client.Timeout = 2 * time.Second
+	client.FragmentTimeout = 100 * time.Millisecond
+
+	if err := getInfo(client); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("first query err = %v, want timeout of stalled fragments", err)
+	}
+
+	// The late fragment of the first query must not cut the deadline of the second one
+	if err := getInfo(client); err != nil {
+		t.Fatalf("second query: %v", err)
+	}
+}
+
+func TestGetAllScenarios(t *testing.T) {
+	tests := []struct {
+		want            error
+		script          Script
+		limits          a2s.Limits
+		name            string
+		timeout         time.Duration
+		fragmentTimeout time.Duration
+	}{
+		{name: "normal", script: Normal()},
+		{name: "compressed", script: Compressed()},
+		{name: "stale fragment", script: StaleFragment()},
+		{name: "info for rules", script: InfoForRules()},
+		{name: "wrong response type", script: WrongResponseType(), want: a2s.ErrValidatorRules},
+		{name: "fragment count limit", script: Compressed(), limits: a2s.Limits{MaxSplitFragments: 1}, want: a2s.ErrMultiPacketCount},
+		{name: "assembled size limit", script: Compressed(), limits: a2s.Limits{MaxAssembledBytes: 16}, want: a2s.ErrMultiPacketSize},
+		{name: "decompressed size limit", script: Compressed(), limits: a2s.Limits{MaxDecompressedSize: 16}, want: a2s.ErrDecompressSize},
+		{name: "stalled fragments", script: StalledFragments(), want: os.ErrDeadlineExceeded, timeout: time.Minute, fragmentTimeout: 100 * time.Millisecond},
+		{name: "endless challenge", script: EndlessChallenge(), want: a2s.ErrValidatorInfo, timeout: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewServer(tt.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = server.Close() }()
+
+			client, err := a2s.NewWithAddr(server.Addr())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = client.Close() }()
+
+			client.Limits = tt.limits
+			client.Timeout = 200 * time.Millisecond
+			if tt.timeout > 0 {
+				client.Timeout = tt.timeout
+			}
+			if tt.fragmentTimeout > 0 {
+				client.FragmentTimeout = tt.fragmentTimeout
+			}
+
+			start := time.Now()
+			all, err := client.GetAll()
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("GetAll took %s", elapsed)
+			}
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("err = %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if all.Info == nil || all.Info.Name != ServerName || !maps.Equal(all.Rules, Rules) {
+				t.Errorf("unexpected result %+v", all)
+			}
+		})
+	}
+}
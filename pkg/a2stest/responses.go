@@ -0,0 +1,168 @@
+package a2stest
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+const (
+	// DefaultFragmentSize is the payload size of split fragments made by scenarios.
+	DefaultFragmentSize = 32
+
+	// ServerName is the name reported in InfoResponse.
+	ServerName = "a2stest"
+
+	// AppID is the game ID reported in InfoResponse.
+	AppID uint16 = 440
+
+	singlePacket = 0xFFFFFFFF
+	multiPacket  = 0xFFFFFFFE
+	compressed   = 0x80000000
+
+	infoResponse      = 'I'
+	playerResponse    = 'D'
+	rulesResponse     = 'E'
+	challengeResponse = 'A'
+)
+
+// Rules are the rules reported in RulesResponse.
+var Rules = map[string]string{
+	"mp_timelimit":    "30",
+	"sv_tags":         "alltalk,increased_maxplayers",
+	"sv_gravity":      "800",
+	"mp_friendlyfire": "0",
+	"sv_password":     "0",
+	"deathmatch":      "1",
+}
+
+// compressedRules is RulesResponse compressed with bzip2.
+var compressedRules = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x1f, 0x51,
+	0x60, 0x30, 0x00, 0x00, 0x3c, 0x5f, 0x80, 0xc1, 0x00, 0x00, 0x04, 0x68,
+	0x40, 0x02, 0x00, 0x00, 0x00, 0xaf, 0xef, 0xdd, 0xe0, 0x00, 0x00, 0xa0,
+	0x00, 0x75, 0x0d, 0x53, 0x43, 0x02, 0x63, 0x4d, 0x4d, 0x03, 0x26, 0x04,
+	0x4c, 0x24, 0xd3, 0x4c, 0x99, 0xa8, 0x03, 0x09, 0xa4, 0x23, 0x3a, 0x7d,
+	0x8b, 0x68, 0x0f, 0x22, 0x08, 0x4b, 0xa8, 0xa0, 0xca, 0x64, 0x01, 0x5b,
+	0xa8, 0x81, 0x48, 0x11, 0x4e, 0x85, 0x72, 0xae, 0x56, 0x46, 0xb8, 0xcc,
+	0x1e, 0xac, 0xff, 0xa1, 0xe8, 0xfa, 0x8f, 0x6a, 0x21, 0x4a, 0xb8, 0xf7,
+	0x33, 0x70, 0xd8, 0xab, 0x02, 0x1c, 0x84, 0x2e, 0xfc, 0x04, 0xe2, 0xf8,
+	0x5c, 0xe4, 0xd4, 0xcc, 0x83, 0xf4, 0xed, 0x90, 0x56, 0x81, 0x1a, 0x30,
+	0xc4, 0x79, 0x16, 0xe1, 0x8e, 0xfc, 0x5d, 0xc9, 0x14, 0xe1, 0x42, 0x40,
+	0x7d, 0x45, 0x80, 0xc0,
+}
+
+// rulesOrder is the order of Rules in RulesResponse, it must match compressedRules.
+var rulesOrder = []string{"mp_timelimit", "sv_tags", "sv_gravity", "mp_friendlyfire", "sv_password", "deathmatch"}
+
+// InfoResponse returns a Source A2S_INFO response of ServerName with AppID.
+func InfoResponse() []byte {
+	packet := header(infoResponse)
+	packet = append(packet, 17)
+	packet = append(packet, ServerName+"\x00map\x00folder\x00Game\x00"...)
+	packet = binary.LittleEndian.AppendUint16(packet, AppID)
+	packet = append(packet, 1, 32, 0, 'd', 'l', 0, 0)
+	packet = append(packet, "1.0\x00"...)
+
+	return append(packet, 0)
+}
+
+// PlayersResponse returns an A2S_PLAYER response with a single player "Player" with score 7 playing for a minute.
+func PlayersResponse() []byte {
+	packet := header(playerResponse)
+	packet = append(packet, 1, 0)
+	packet = append(packet, "Player\x00"...)
+	packet = binary.LittleEndian.AppendUint32(packet, 7)
+
+	return binary.LittleEndian.AppendUint32(packet, math.Float32bits(60))
+}
+
+// RulesResponse returns an A2S_RULES response with Rules.
+func RulesResponse() []byte {
+	packet := header(rulesResponse)
+	packet = binary.LittleEndian.AppendUint16(packet, uint16(len(rulesOrder)))
+	for _, key := range rulesOrder {
+		packet = append(packet, key+"\x00"+Rules[key]+"\x00"...)
+	}
+
+	return packet
+}
+
+// ChallengeResponse returns an S2C_CHALLENGE response with challenge.
+func ChallengeResponse(challenge uint32) []byte {
+	return binary.BigEndian.AppendUint32(header(challengeResponse), challenge)
+}
+
+// Response returns the valid response to the request type, nil for unsupported types.
+func Response(request a2s.Flag) []byte {
+	switch request {
+	case a2s.InfoRequest:
+		return InfoResponse()
+	case a2s.PlayerRequest:
+		return PlayersResponse()
+	case a2s.RulesRequest:
+		return RulesResponse()
+	}
+
+	return nil
+}
+
+// Split splits a single packet response into Source split fragments of id
+// with at most size payload bytes each. Fragments are returned in order.
+func Split(id uint32, response []byte, size int) [][]byte {
+	if size <= 0 {
+		size = DefaultFragmentSize
+	}
+
+	count := (len(response) + size - 1) / size
+	fragments := make([][]byte, 0, count)
+	for i := range count {
+		part := response[i*size : min((i+1)*size, len(response))]
+		fragments = append(fragments, append(splitHeader(id&^compressed, count, i), part...))
+	}
+
+	return fragments
+}
+
+// SplitCompressed splits the bzip2 compressed RulesResponse into Source split fragments of id.
+// The first fragment carries the decompressed size and crc, use CRC for a valid checksum.
+func SplitCompressed(id uint32, crc uint32, size int) [][]byte {
+	if size <= 0 {
+		size = DefaultFragmentSize
+	}
+
+	count := (len(compressedRules) + size - 1) / size
+	fragments := make([][]byte, 0, count)
+	for i := range count {
+		packet := splitHeader(id|compressed, count, i)
+		if i == 0 {
+			packet = binary.LittleEndian.AppendUint32(packet, uint32(len(RulesResponse())))
+			packet = binary.LittleEndian.AppendUint32(packet, crc)
+		}
+		part := compressedRules[i*size : min((i+1)*size, len(compressedRules))]
+		fragments = append(fragments, append(packet, part...))
+	}
+
+	return fragments
+}
+
+// CRC returns the CRC32 of the decompressed RulesResponse for SplitCompressed.
+func CRC() uint32 {
+	return crc32.ChecksumIEEE(RulesResponse())
+}
+
+// header returns a single packet header with response type.
+func header(response byte) []byte {
+	return append(binary.LittleEndian.AppendUint32(nil, singlePacket), response)
+}
+
+// splitHeader returns a Source split header of fragment index out of count.
+func splitHeader(id uint32, count, index int) []byte {
+	packet := binary.LittleEndian.AppendUint32(nil, multiPacket)
+	packet = binary.LittleEndian.AppendUint32(packet, id)
+	packet = append(packet, byte(count), byte(index))
+
+	return binary.LittleEndian.AppendUint16(packet, 1248)
+}
@@ -0,0 +1,231 @@
+package a2stest
+
+import (
+	"slices"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+// splitID is the split ID of fragments sent by scenarios.
+const splitID uint32 = 0x0A2D
+
+// Normal answers every request with the valid response.
+func Normal() Script {
+	return func(req Request) []Packet {
+		return single(Response(req.Type))
+	}
+}
+
+// WithChallenge answers requests not carrying challenge with S2C_CHALLENGE
+// and passes requests with it to script.
+func WithChallenge(challenge uint32, script Script) Script {
+	return func(req Request) []Packet {
+		if req.Challenge != challenge {
+			return single(ChallengeResponse(challenge))
+		}
+
+		return script(req)
+	}
+}
+
+// TruncatedFirstPacket sends a split header cut to 5 bytes before the valid response.
+func TruncatedFirstPacket() Script {
+	return func(req Request) []Packet {
+		response := Response(req.Type)
+		if response == nil {
+			return nil
+		}
+
+		return []Packet{{Data: splitHeader(splitID, 2, 0)[:5]}, {Data: response}}
+	}
+}
+
+// OutOfOrderFragments splits the valid response and sends fragments in reverse order.
+func OutOfOrderFragments() Script {
+	return func(req Request) []Packet {
+		fragments := Split(splitID, Response(req.Type), DefaultFragmentSize)
+		slices.Reverse(fragments)
+
+		return packets(fragments...)
+	}
+}
+
+// DuplicatedFragments splits the valid response and sends every fragment twice.
+func DuplicatedFragments() Script {
+	return func(req Request) []Packet {
+		var fragments [][]byte
+		for _, fragment := range Split(splitID, Response(req.Type), DefaultFragmentSize) {
+			fragments = append(fragments, fragment, fragment)
+		}
+
+		return packets(fragments...)
+	}
+}
+
+// InterleavedSplitIDs splits the valid response and sends fragments of another split ID,
+// e.g. late fragments of a previous query, before and after the first fragment.
+func InterleavedSplitIDs() Script {
+	return func(req Request) []Packet {
+		fragments := Split(splitID, Response(req.Type), DefaultFragmentSize)
+		if len(fragments) == 0 {
+			return nil
+		}
+		stale := Split(splitID-1, RulesResponse(), DefaultFragmentSize)
+
+		fragments = slices.Insert(fragments, 1, stale[len(stale)-1])
+		return packets(slices.Insert(fragments, 0, stale[len(stale)-2])...)
+	}
+}
+
+// StaleFragment sends a fragment of another split ID, e.g. a late fragment of a previous
+// timed out query, followed by the valid response in a single packet.
+func StaleFragment() Script {
+	return func(req Request) []Packet {
+		response := Response(req.Type)
+		if response == nil {
+			return nil
+		}
+		stale := Split(splitID-1, RulesResponse(), DefaultFragmentSize)
+
+		return packets(stale[0], response)
+	}
+}
+
+// LateFragment answers the first request with only the first fragment of the split response,
+// so the query times out, and later requests with the late second fragment of that response
+// followed by the valid response in a single packet after delay.
+func LateFragment(delay time.Duration) Script {
+	return func(req Request) []Packet {
+		response := Response(req.Type)
+		if response == nil {
+			return nil
+		}
+		stale := Split(splitID, response, DefaultFragmentSize)
+		if req.Seq == 0 {
+			return packets(stale[0])
+		}
+
+		return []Packet{{Data: stale[1]}, {Data: response, Delay: delay}}
+	}
+}
+
+// OutOfRangeFragment splits the valid response and sends a fragment numbered beyond the fragment count first.
+func OutOfRangeFragment() Script {
+	return func(req Request) []Packet {
+		fragments := Split(splitID, Response(req.Type), DefaultFragmentSize)
+		if len(fragments) == 0 {
+			return nil
+		}
+
+		bogus := slices.Clone(fragments[len(fragments)-1])
+		bogus[9] = byte(len(fragments))
+
+		return packets(slices.Insert(fragments, 0, bogus)...)
+	}
+}
+
+// StalledFragments splits the valid response and sends only the first fragment.
+func StalledFragments() Script {
+	return func(req Request) []Packet {
+		fragments := Split(splitID, Response(req.Type), DefaultFragmentSize)
+		if len(fragments) == 0 {
+			return nil
+		}
+
+		return packets(fragments[0])
+	}
+}
+
+// EndlessChallenge answers every request with a new challenge.
+func EndlessChallenge() Script {
+	return func(req Request) []Packet {
+		return single(ChallengeResponse(0x10000000 + uint32(req.Seq)))
+	}
+}
+
+// WrongResponseType answers A2S_INFO with A2S_PLAYER response and other requests with A2S_INFO response.
+func WrongResponseType() Script {
+	return func(req Request) []Packet {
+		if req.Type == a2s.InfoRequest {
+			return single(PlayersResponse())
+		}
+
+		return single(InfoResponse())
+	}
+}
+
+// InfoForRules answers the first A2S_RULES request with A2S_INFO response, as some servers do,
+// and other requests normally.
+func InfoForRules() Script {
+	rules := 0
+	return func(req Request) []Packet {
+		if req.Type == a2s.RulesRequest {
+			if rules++; rules == 1 {
+				return single(InfoResponse())
+			}
+		}
+
+		return single(Response(req.Type))
+	}
+}
+
+// Compressed answers A2S_RULES with bzip2 compressed split fragments and other requests normally.
+func Compressed() Script {
+	return compressedScript(CRC())
+}
+
+// CorruptedCRC answers A2S_RULES with bzip2 compressed split fragments carrying a wrong CRC
+// and other requests normally.
+func CorruptedCRC() Script {
+	return compressedScript(^CRC())
+}
+
+// Slow answers every request with the valid response after delay.
+func Slow(delay time.Duration) Script {
+	return func(req Request) []Packet {
+		response := Response(req.Type)
+		if response == nil {
+			return nil
+		}
+
+		return []Packet{{Data: response, Delay: delay}}
+	}
+}
+
+// Silence never answers.
+func Silence() Script {
+	return func(Request) []Packet {
+		return nil
+	}
+}
+
+// compressedScript answers A2S_RULES with compressed fragments carrying crc.
+func compressedScript(crc uint32) Script {
+	return func(req Request) []Packet {
+		if req.Type != a2s.RulesRequest {
+			return single(Response(req.Type))
+		}
+
+		return packets(SplitCompressed(splitID, crc, DefaultFragmentSize)...)
+	}
+}
+
+// single returns a packet with data or nil if data is nil.
+func single(data []byte) []Packet {
+	if data == nil {
+		return nil
+	}
+
+	return []Packet{{Data: data}}
+}
+
+// packets wraps datagrams into packets sent without delay.
+func packets(datagrams ...[]byte) []Packet {
+	result := make([]Packet, 0, len(datagrams))
+	for _, data := range datagrams {
+		result = append(result, Packet{Data: data})
+	}
+
+	return result
+}
@@ -0,0 +1,148 @@
+// Package a2stest provides a scripted fake A2S server to test code built on a2s
+// against misbehaving servers without real ones.
+//
+// A Server answers every request with the packets returned by its Script.
+// Scenarios cover truncated and split packets, challenge loops, wrong response types,
+// corrupted compression, slow responders and silence:
+//
+//	server, err := a2stest.NewServer(a2stest.OutOfOrderFragments())
+//	defer server.Close()
+//	client, err := a2s.NewWithAddr(server.Addr())
+package a2stest
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+// infoRequestSize is the size of A2S_INFO request without challenge.
+const infoRequestSize = 4 + 1 + len("Source Engine Query") + 1
+
+// Request is a request received by the server.
+type Request struct {
+	Type      a2s.Flag // Request type, e.g. a2s.InfoRequest
+	Challenge uint32   // Challenge of the request, a2s.NoChallenge if sent without
+	Seq       int      // Number of requests received before this one
+}
+
+// Packet is a datagram sent by the server.
+type Packet struct {
+	Data  []byte        // Raw datagram including the single or split packet header
+	Delay time.Duration // Time to wait before sending, counted from the previous packet
+}
+
+// Script returns the packets answering the request, nil to stay silent.
+type Script func(req Request) []Packet
+
+// Server is a fake A2S server on a local UDP port.
+type Server struct {
+	conn     *net.UDPConn
+	script   Script
+	done     chan struct{}
+	wg       sync.WaitGroup
+	requests atomic.Int32
+}
+
+// NewServer starts a server on a random 127.0.0.1 UDP port answering requests with script.
+func NewServer(script Script) (*Server, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{conn: conn, script: script, done: make(chan struct{})}
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Addr returns the server address.
+func (s *Server) Addr() *net.UDPAddr {
+	return s.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// Requests returns the number of requests received.
+func (s *Server) Requests() int {
+	return int(s.requests.Load())
+}
+
+// Close stops the server, pending delayed packets are dropped.
+func (s *Server) Close() error {
+	close(s.done)
+	err := s.conn.Close()
+	s.wg.Wait()
+
+	return err
+}
+
+// serve reads requests and sends script replies, every reply is sent concurrently
+// so a slow reply does not block following requests.
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	buf := make([]byte, 1400)
+	for {
+		n, addr, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		req, ok := parseRequest(buf[:n])
+		if !ok {
+			continue
+		}
+		req.Seq = int(s.requests.Add(1)) - 1
+
+		packets := s.script(req)
+		if len(packets) == 0 {
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.send(packets, addr)
+	}
+}
+
+// send writes packets to addr honoring their delays.
+func (s *Server) send(packets []Packet, addr *net.UDPAddr) {
+	defer s.wg.Done()
+
+	for _, packet := range packets {
+		if packet.Delay > 0 {
+			timer := time.NewTimer(packet.Delay)
+			select {
+			case <-s.done:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		if _, err := s.conn.WriteToUDP(packet.Data, addr); err != nil {
+			return
+		}
+	}
+}
+
+// parseRequest reads request type and challenge of an A2S request.
+func parseRequest(data []byte) (Request, bool) {
+	if len(data) < 5 || binary.LittleEndian.Uint32(data[:4]) != a2s.NoChallenge {
+		return Request{}, false
+	}
+
+	req := Request{Type: a2s.Flag(data[4]), Challenge: a2s.NoChallenge}
+	switch {
+	case req.Type == a2s.InfoRequest && len(data) >= infoRequestSize+4:
+		req.Challenge = binary.BigEndian.Uint32(data[infoRequestSize:])
+	case req.Type != a2s.InfoRequest && len(data) >= 9:
+		req.Challenge = binary.BigEndian.Uint32(data[5:9])
+	}
+
+	return req, true
+}
@@ -0,0 +1,68 @@
+package a3sb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+// All contains server info, players and rules queried in one round trip.
+// Rules are parsed with A3SB for Arma 3 and DayZ, RawRules are set for other games.
+type All struct {
+	Info     *a2s.Info         `json:"info,omitempty"`      // A2S_INFO response
+	Rules    *Rules            `json:"rules,omitempty"`     // A3SB rules, Arma 3 and DayZ only
+	RawRules map[string]string `json:"raw_rules,omitempty"` // Standard A2S_RULES of other games
+	Players  []a2s.Player      `json:"players,omitempty"`   // A2S_PLAYER response
+}
+
+// GetAll sends A2S_INFO, A2S_PLAYER and A2S_RULES together sharing a single challenge
+// and chooses the rules parser by Info.ID. Parts that were received and parsed are returned
+// even if the error is not nil.
+func (c *Client) GetAll() (*All, error) {
+	return c.GetAllGame(0)
+}
+
+// GetAllGame is GetAll with rules parsed with A3SB for the game AppID regardless of Info.ID,
+// zero game chooses the parser by Info.ID.
+func (c *Client) GetAllGame(game uint64) (*All, error) {
+	defer c.rulesBuffer()()
+
+	responses, err := c.GetPipelined(a2s.InfoRequest, a2s.PlayerRequest, a2s.RulesRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	all := &All{}
+	var errs []error
+
+	if all.Info, err = responses[0].Info(); err != nil {
+		errs = append(errs, err)
+	}
+	if all.Players, err = responses[1].Players(); err != nil {
+		errs = append(errs, err)
+	}
+	if game == 0 && all.Info != nil {
+		game = all.Info.ID
+	}
+
+	switch rules := responses[2]; {
+	case game == 0:
+		// Without AppID the rules parser can't be chosen
+
+	case game == appid.Arma3.Uint64(), game == appid.DayZ.Uint64(), game == appid.DayZExp.Uint64():
+		if rules.Err != nil {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrRules, rules.Err))
+		} else if all.Rules, err = parseRules(rules.Data, game, c.catalog(), c.Limits); err != nil {
+			errs = append(errs, err)
+		}
+
+	default:
+		if all.RawRules, err = rules.Rules(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return all, errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package a3sb
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a2stest"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+func TestGetAllGame(t *testing.T) {
+	server, err := a2stest.NewServer(a2stest.Normal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }()
+
+	client, err := a2s.NewWithAddr(server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	client.Timeout = time.Second
+	a3sbClient := &Client{Client: client}
+
+	all, err := a3sbClient.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all.Rules != nil || !maps.Equal(all.RawRules, a2stest.Rules) {
+		t.Errorf("GetAll detected A3SB rules for AppID %d: %+v", all.Info.ID, all)
+	}
+
+	all, err = a3sbClient.GetAllGame(appid.Arma3.Uint64())
+	if all == nil || all.Info == nil || all.RawRules != nil {
+		t.Fatalf("GetAllGame did not force A3SB parser: %+v", all)
+	}
+	if all.Rules == nil && err == nil {
+		t.Error("GetAllGame returned neither A3SB rules nor error")
+	}
+	if client.BufferSize != a2s.DefaultBufferSize {
+		t.Errorf("GetAllGame left buffer size %d, want %d", client.BufferSize, a2s.DefaultBufferSize)
+	}
+}
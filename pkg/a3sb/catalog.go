@@ -0,0 +1,219 @@
+package a3sb
+
+import (
+	_ "embed" // embed default DLC catalog
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/woozymasta/steam/utils/appid"
+	"gopkg.in/yaml.v3"
+)
+
+// Default DLC catalog
+//   - https://steamdb.info/app/221100/dlc/
+//   - https://steamdb.info/app/107410/dlc/
+//   - https://community.bistudio.com/wiki/Category:Arma_3:_DLCs_%26_Expansions
+//
+//go:embed dlc_catalog.json
+var embeddedCatalog []byte
+
+// defaultCatalog is the catalog used by clients without own catalog.
+var defaultCatalog atomic.Pointer[Catalog]
+
+// Catalog contains DLC and Creator DLC names and Steam AppIDs used to decode A3SB responses.
+// DLC are matched by bit index in the DLC mask, Creator DLC by Steam AppID.
+type Catalog struct {
+	Arma3   GameCatalog `json:"arma3" yaml:"arma3"`                         // Arma 3 DLC and Creator DLC
+	DayZ    GameCatalog `json:"dayz" yaml:"dayz"`                           // DayZ DLC
+	Replace bool        `json:"replace,omitempty" yaml:"replace,omitempty"` // Replace the catalog instead of extending it on merge
+}
+
+// GameCatalog contains DLC lists of a single game.
+type GameCatalog struct {
+	DLC        []CatalogEntry `json:"dlc,omitempty" yaml:"dlc,omitempty"`                 // DLC by bit index in the DLC mask
+	CreatorDLC []CatalogEntry `json:"creator_dlc,omitempty" yaml:"creator_dlc,omitempty"` // Creator DLC by Steam AppID
+}
+
+// CatalogEntry describes a single DLC.
+type CatalogEntry struct {
+	Name string `json:"name" yaml:"name"`                   // DLC name
+	ID   uint64 `json:"id" yaml:"id"`                       // DLC Steam AppID
+	Bit  *uint8 `json:"bit,omitempty" yaml:"bit,omitempty"` // Bit index in the DLC mask (0-15), required for DLC only
+}
+
+func init() {
+	catalog, err := ParseCatalog(embeddedCatalog, ".json")
+	if err != nil {
+		panic(err)
+	}
+	defaultCatalog.Store(catalog)
+}
+
+// DefaultCatalog returns the catalog used by clients without own catalog.
+func DefaultCatalog() *Catalog {
+	return defaultCatalog.Load()
+}
+
+// SetDefaultCatalog replaces the catalog used by clients without own catalog.
+// Passing nil restores the embedded catalog.
+func SetDefaultCatalog(catalog *Catalog) {
+	if catalog == nil {
+		catalog, _ = ParseCatalog(embeddedCatalog, ".json")
+	}
+	defaultCatalog.Store(catalog)
+}
+
+// LoadCatalog reads a catalog from JSON or YAML file, the format is chosen by file extension.
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := ParseCatalog(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return catalog, nil
+}
+
+// ParseCatalog parses a catalog from data, ext is the file extension (".json", ".yaml" or ".yml").
+func ParseCatalog(data []byte, ext string) (*Catalog, error) {
+	catalog := &Catalog{}
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		if err := json.Unmarshal(data, catalog); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCatalog, err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, catalog); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCatalog, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrCatalog, ext)
+	}
+
+	for _, game := range []*GameCatalog{&catalog.Arma3, &catalog.DayZ} {
+		for _, entry := range game.DLC {
+			switch {
+			case entry.Bit == nil:
+				return nil, fmt.Errorf("%w: DLC %q has no bit", ErrCatalog, entry.Name)
+			case *entry.Bit > 15:
+				return nil, fmt.Errorf("%w: DLC %q bit %d out of range 0-15", ErrCatalog, entry.Name, *entry.Bit)
+			}
+		}
+	}
+
+	return catalog, nil
+}
+
+// Merge returns a new catalog with entries of other added to c, entries with the same
+// bit (DLC) or AppID (Creator DLC) are overridden, DLC without bit are skipped.
+// If other.Replace is set, a copy of other is returned.
+func (c *Catalog) Merge(other *Catalog) *Catalog {
+	if other == nil {
+		return c.clone()
+	}
+	if other.Replace {
+		merged := other.clone()
+		merged.Replace = false
+		return merged
+	}
+
+	merged := c.clone()
+	merged.Arma3.merge(&other.Arma3)
+	merged.DayZ.merge(&other.DayZ)
+
+	return merged
+}
+
+// Game returns the catalog of the game with given Steam AppID or nil if the game is unknown.
+func (c *Catalog) Game(id uint64) *GameCatalog {
+	if c == nil {
+		return nil
+	}
+
+	switch id {
+	case appid.Arma3.Uint64():
+		return &c.Arma3
+	case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
+		return &c.DayZ
+	}
+
+	return nil
+}
+
+// clone returns a deep copy of the catalog.
+func (c *Catalog) clone() *Catalog {
+	return &Catalog{Arma3: c.Arma3.clone(), DayZ: c.DayZ.clone(), Replace: c.Replace}
+}
+
+// clone returns a deep copy of the game catalog.
+func (g GameCatalog) clone() GameCatalog {
+	return GameCatalog{
+		DLC:        append([]CatalogEntry(nil), g.DLC...),
+		CreatorDLC: append([]CatalogEntry(nil), g.CreatorDLC...),
+	}
+}
+
+// merge adds or overrides entries of other in the game catalog.
+func (g *GameCatalog) merge(other *GameCatalog) {
+	for _, entry := range other.DLC {
+		if entry.Bit == nil {
+			continue
+		}
+		if i := g.dlcIndex(*entry.Bit); i >= 0 {
+			g.DLC[i] = entry
+		} else {
+			g.DLC = append(g.DLC, entry)
+		}
+	}
+
+	for _, entry := range other.CreatorDLC {
+		if i := g.creatorIndex(entry.ID); i >= 0 {
+			g.CreatorDLC[i] = entry
+		} else {
+			g.CreatorDLC = append(g.CreatorDLC, entry)
+		}
+	}
+}
+
+// dlcIndex returns index of the DLC with given bit or -1.
+func (g *GameCatalog) dlcIndex(bit uint8) int {
+	for i, entry := range g.DLC {
+		if entry.Bit != nil && *entry.Bit == bit {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// creatorIndex returns index of the Creator DLC with given AppID or -1.
+func (g *GameCatalog) creatorIndex(id uint64) int {
+	for i, entry := range g.CreatorDLC {
+		if entry.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// CreatorDLCName returns Creator DLC name by Steam AppID or empty string if unknown.
+func (g *GameCatalog) CreatorDLCName(id uint64) string {
+	if g == nil {
+		return ""
+	}
+	if i := g.creatorIndex(id); i >= 0 {
+		return g.CreatorDLC[i].Name
+	}
+
+	return ""
+}
@@ -0,0 +1,98 @@
+package a3sb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+func TestDefaultCatalog(t *testing.T) {
+	catalog := DefaultCatalog()
+
+	dlc := parseDLC(0x0011, catalog.Game(appid.Arma3.Uint64()))
+	if len(dlc) != 2 || dlc[0].Name != "Karts" || dlc[1].Name != "Apex" {
+		t.Errorf("parseDLC() = %+v", dlc)
+	}
+
+	if name := catalog.Game(appid.Arma3.Uint64()).CreatorDLCName(1681170); name != "Creator DLC: Western Sahara" {
+		t.Errorf("CreatorDLCName() = %q", name)
+	}
+}
+
+func TestParseDLCUnknownBits(t *testing.T) {
+	dlc := parseDLC(0x8003, DefaultCatalog().Game(appid.DayZ.Uint64()))
+	if len(dlc) != 3 {
+		t.Fatalf("parseDLC() = %+v", dlc)
+	}
+
+	// hashes follow the mask in ascending bit order
+	if dlc[0].Name != "Livonia" || dlc[1].Name != "Frost Line" || dlc[2].Name != "Unknown DLC bit 15" || dlc[2].ID != 0 {
+		t.Errorf("parseDLC() = %+v", dlc)
+	}
+
+	if dlc := parseDLC(0x1, nil); len(dlc) != 1 || dlc[0].Name != "Unknown DLC bit 0" {
+		t.Errorf("parseDLC() for unknown game = %+v", dlc)
+	}
+}
+
+func TestLoadCatalogMerge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dlc.yaml")
+	data := `dayz:
+  dlc:
+    - bit: 4
+      id: 4000000
+      name: New Map
+    - bit: 0
+      id: 1151700
+      name: Livonia Renamed
+arma3:
+  creator_dlc:
+    - id: 3000000
+      name: "Creator DLC: New"
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	override, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := DefaultCatalog().Merge(override)
+	dayz := merged.Game(appid.DayZ.Uint64())
+	if len(dayz.DLC) != 5 {
+		t.Errorf("merged DayZ DLC = %+v", dayz.DLC)
+	}
+
+	dlc := parseDLC(0x11, dayz)
+	if len(dlc) != 2 || dlc[0].Name != "Livonia Renamed" || dlc[1].Name != "New Map" {
+		t.Errorf("parseDLC() with merged catalog = %+v", dlc)
+	}
+	if merged.Game(appid.Arma3.Uint64()).CreatorDLCName(3000000) != "Creator DLC: New" {
+		t.Error("creator DLC was not merged")
+	}
+
+	// default catalog is not modified by merge
+	if len(DefaultCatalog().Game(appid.DayZ.Uint64()).DLC) != 4 {
+		t.Error("Merge() modified the default catalog")
+	}
+
+	override.Replace = true
+	if replaced := DefaultCatalog().Merge(override); len(replaced.Arma3.DLC) != 0 {
+		t.Errorf("Merge() with replace kept default entries: %+v", replaced.Arma3.DLC)
+	}
+
+	if _, err := ParseCatalog([]byte(`{"dayz":{"dlc":[{"bit":16,"id":1}]}}`), ".json"); err == nil {
+		t.Error("ParseCatalog() accepted bit out of range")
+	}
+	if _, err := ParseCatalog([]byte(`{"arma3":{"dlc":[{"id":1,"name":"No Bit"}]}}`), ".json"); !errors.Is(err, ErrCatalog) {
+		t.Errorf("ParseCatalog() accepted DLC without bit: %v", err)
+	}
+	if _, err := ParseCatalog([]byte("arma3:\n  dlc:\n    - {id: 1, name: No Bit}\n"), ".yaml"); !errors.Is(err, ErrCatalog) {
+		t.Errorf("ParseCatalog() accepted YAML DLC without bit: %v", err)
+	}
+}
@@ -2,7 +2,29 @@ package a3sb
 
 import "github.com/woozymasta/a2s/pkg/a2s"
 
-// Client A2S Override
+// Client A2S Override. Decoding limits, including Limits.MaxMods, are taken from the embedded client.
 type Client struct {
 	*a2s.Client
+	Catalog *Catalog // DLC catalog, DefaultCatalog() is used if nil
+}
+
+// rulesBuffer raises the default read buffer to DefaultRulesBufferSize for A3SB rules
+// and returns a function restoring the previous size.
+func (c *Client) rulesBuffer() func() {
+	size := c.BufferSize
+	if size != a2s.DefaultBufferSize {
+		return func() {}
+	}
+
+	c.SetBufferSize(DefaultRulesBufferSize)
+	return func() { c.SetBufferSize(size) }
+}
+
+// catalog returns the client DLC catalog or the default one.
+func (c *Client) catalog() *Catalog {
+	if c.Catalog != nil {
+		return c.Catalog
+	}
+
+	return DefaultCatalog()
 }
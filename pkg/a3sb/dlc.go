@@ -5,7 +5,6 @@ import (
 	"math/bits"
 
 	"github.com/woozymasta/a2s/internal/bread"
-	"github.com/woozymasta/steam/utils/appid"
 )
 
 // DLC 3rd and 4th bytes of the server browser protocol store the DLC bitmask flags
@@ -18,51 +17,11 @@ type DLCInfo struct {
 	Hash uint32 `json:"hash,omitempty"` // DLC short hash
 }
 
-// DayZ DLC Map for DLC byte blocks
-//   - https://steamdb.info/app/221100/dlc/
-var dayzDLC = map[DLC]DLCInfo{
-	0x1: {ID: 1151700, Name: "Livonia"},
-	0x2: {ID: 2968040, Name: "Frost Line"},
-	0x4: {ID: 3816030, Name: "Badlands"},
-	0x8: {ID: 830660, Name: "Survivor GameZ"},
-}
-
-// Arma 3 DLC Map for DLC byte blocks
-//   - https://community.bistudio.com/wiki/Category:Arma_3:_DLCs_%26_Expansions
-//   - https://steamdb.info/app/107410/dlc/
-var arma3DLC = map[DLC]DLCInfo{
-	0x1:    {ID: 288520, Name: "Karts"},
-	0x2:    {ID: 332350, Name: "Marksmen"},
-	0x4:    {ID: 304380, Name: "Helicopters"},
-	0x8:    {ID: 275700, Name: "Zeus"},
-	0x10:   {ID: 395180, Name: "Apex"},
-	0x20:   {ID: 601670, Name: "Jets"},
-	0x40:   {ID: 571710, Name: "Laws of War"},
-	0x80:   {ID: 639600, Name: "Malden"},
-	0x100:  {ID: 744950, Name: "Tac-Ops Mission Pack"},
-	0x200:  {ID: 798390, Name: "Tanks"},
-	0x400:  {ID: 1021790, Name: "Enoch"},
-	0x800:  {ID: 1021790, Name: "Contact (Platform)"},
-	0x1000: {ID: 1325500, Name: "Art of War"},
-}
-
 // readDLC parses DLC information from bitmask and reads hashes.
 func (r *Rules) readDLC(reader *bread.Reader, dlcMask uint16) error {
-	switch r.id {
-	case appid.Arma3.Uint64():
-		r.DLC = parseDLC(dlcMask, arma3DLC)
-	case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
-		r.DLC = parseDLC(dlcMask, dayzDLC)
-	default:
-		r.DLC = parseDLC(dlcMask, map[DLC]DLCInfo{})
-	}
+	r.DLC = parseDLC(DLC(dlcMask), r.catalog.Game(r.id))
 
-	dlcCount := len(r.DLC)
-	if dlcCount == 0 {
-		return nil
-	}
-
-	for i := 0; i < dlcCount; i++ {
+	for i := range r.DLC {
 		hash, err := reader.Uint32()
 		if err != nil {
 			return err
@@ -73,36 +32,30 @@ func (r *Rules) readDLC(reader *bread.Reader, dlcMask uint16) error {
 	return nil
 }
 
-// parseDLC parses DLC bitmask into DLCInfo slice.
-func parseDLC(mask uint16, dlcs map[DLC]DLCInfo) []DLCInfo {
-	dlc := DLC(mask)
-
-	bitCount := bits.OnesCount16(uint16(dlc))
+// parseDLC parses DLC bitmask into DLCInfo slice in ascending bit order,
+// which is the order of DLC hashes in the response. Bits missing in the
+// catalog are reported as unknown DLC with the bit index.
+func parseDLC(mask DLC, game *GameCatalog) []DLCInfo {
+	bitCount := bits.OnesCount16(uint16(mask))
 	if bitCount == 0 {
 		return nil
 	}
 
 	result := make([]DLCInfo, 0, bitCount)
 
-	// Processing of known DLCs
-	for bit, info := range dlcs {
-		if dlc&bit != 0 {
-			result = append(result, info)
-			dlc &^= bit // Removing match DLC from the mask
+	for bit := uint8(0); bit < 16; bit++ {
+		if mask&(1<<bit) == 0 {
+			continue
 		}
-	}
 
-	// Checking the remaining bits for unknown DLCs
-	bit := DLC(1) // Start with the least significant bit
-	for dlc != 0 {
-		if dlc&bit != 0 {
-			result = append(result, DLCInfo{
-				ID:   0,
-				Name: fmt.Sprintf("Unknown DLC %d", bit),
-			})
-			dlc &^= bit // Remove the processed bit from the mask
+		if game != nil {
+			if i := game.dlcIndex(bit); i >= 0 {
+				result = append(result, DLCInfo{ID: game.DLC[i].ID, Name: game.DLC[i].Name})
+				continue
+			}
 		}
-		bit <<= 1 // Move on to the next bit
+
+		result = append(result, DLCInfo{Name: fmt.Sprintf("Unknown DLC bit %d", bit)})
 	}
 
 	return result
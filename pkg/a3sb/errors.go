@@ -20,4 +20,12 @@ var (
 	ErrMod         = errors.New(errorPrefix + "mod")         // error in read a3sb mod
 	ErrSignature   = errors.New(errorPrefix + "signature")   // error in read a3sb signature
 	ErrDescription = errors.New(errorPrefix + "description") // error in read a3sb description
+
+	ErrModCountLimit = errors.New("A2S_RULES: A3SB mod count exceeds limit") // error mod count over a2s.Limits.MaxMods
+
+	ErrWorkshopMeta = errors.New("workshop: fail read mod meta.cpp") // error in read local mod meta.cpp
+	ErrBiKey        = errors.New("bikey: fail read public key")      // error in read local .bikey file
+	ErrCatalog      = errors.New("catalog: fail read DLC catalog")   // error in read DLC catalog file
+
+	ErrModName = errors.New("launch: mod name can not be passed in -mod= parameter") // error mod name with ";" or double quote
 )
@@ -0,0 +1,147 @@
+package a3sb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/woozymasta/a2s/internal/bread"
+)
+
+// bikeyMagic is the RSA public key blob magic following the key name in .bikey files.
+const bikeyMagic = "RSA1"
+
+// BiKey contains information read from a BattlEye/Bohemia public key (.bikey) file.
+type BiKey struct {
+	Name string `json:"name"`           // Key name stored in the file header, matches server signatures
+	Path string `json:"path,omitempty"` // Path to the key file
+	Bits uint32 `json:"bits,omitempty"` // RSA modulus length in bits
+}
+
+// SignatureReport is the result of comparing server signatures against local keys,
+// every field is seen from the server signatures.
+type SignatureReport struct {
+	Present    []string `json:"present,omitempty"`    // Server signatures with a local key
+	Missing    []string `json:"missing,omitempty"`    // Server signatures without a local key
+	Unexpected []BiKey  `json:"unexpected,omitempty"` // Local keys not among the server signatures
+}
+
+// ParseBiKey parses a .bikey file content. The file layout is a null-terminated key name,
+// uint32 blob length and a CryptoAPI PUBLICKEYBLOB with the RSA public key.
+func ParseBiKey(data []byte) (BiKey, error) {
+	reader := bread.NewReader(data)
+
+	name, err := reader.String()
+	if err != nil {
+		return BiKey{}, fmt.Errorf("%w name: %w", ErrBiKey, err)
+	}
+	if name == "" {
+		return BiKey{}, fmt.Errorf("%w: empty key name", ErrBiKey)
+	}
+
+	blobLen, err := reader.Uint32()
+	if err != nil {
+		return BiKey{}, fmt.Errorf("%w blob length: %w", ErrBiKey, err)
+	}
+	if int(blobLen) != reader.Len() {
+		return BiKey{}, fmt.Errorf("%w: blob length %d, got %d bytes", ErrBiKey, blobLen, reader.Len())
+	}
+
+	// PUBLICKEYBLOB: bType, bVersion, reserved(2), aiKeyAlg(4), then RSAPUBKEY: magic, bitlen, pubexp
+	if _, err := reader.StringLen(8); err != nil {
+		return BiKey{}, fmt.Errorf("%w blob header: %w", ErrBiKey, err)
+	}
+	magic, err := reader.StringLen(4)
+	if err != nil {
+		return BiKey{}, fmt.Errorf("%w blob magic: %w", ErrBiKey, err)
+	}
+	if magic != bikeyMagic {
+		return BiKey{}, fmt.Errorf("%w: unexpected blob magic %q", ErrBiKey, magic)
+	}
+	bits, err := reader.Uint32()
+	if err != nil {
+		return BiKey{}, fmt.Errorf("%w key length: %w", ErrBiKey, err)
+	}
+
+	return BiKey{Name: name, Bits: bits}, nil
+}
+
+// ReadBiKey reads and parses a .bikey file.
+func ReadBiKey(path string) (BiKey, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return BiKey{}, err
+	}
+
+	key, err := ParseBiKey(data)
+	if err != nil {
+		return BiKey{}, fmt.Errorf("%s: %w", path, err)
+	}
+	key.Path = path
+
+	return key, nil
+}
+
+// ScanKeys reads all .bikey files from a directory, e.g. the server keys/ folder.
+func ScanKeys(dir string) ([]BiKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]BiKey, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".bikey") {
+			continue
+		}
+
+		key, err := ReadBiKey(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// CompareSignatures matches server signatures against local keys by key name (case-insensitive).
+func (r *Rules) CompareSignatures(keys []BiKey) *SignatureReport {
+	report := &SignatureReport{}
+
+	accepted := make(map[string]struct{}, len(r.Signatures))
+	for _, signature := range r.Signatures {
+		accepted[strings.ToLower(signature)] = struct{}{}
+	}
+
+	local := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		name := strings.ToLower(key.Name)
+		local[name] = struct{}{}
+
+		if _, ok := accepted[name]; ok {
+			report.Present = append(report.Present, key.Name)
+		} else {
+			report.Unexpected = append(report.Unexpected, key)
+		}
+	}
+
+	for _, signature := range r.Signatures {
+		if _, ok := local[strings.ToLower(signature)]; !ok {
+			report.Missing = append(report.Missing, signature)
+		}
+	}
+
+	sort.Strings(report.Present)
+	sort.Strings(report.Missing)
+	sort.Slice(report.Unexpected, func(i, j int) bool { return report.Unexpected[i].Name < report.Unexpected[j].Name })
+
+	return report
+}
+
+// OK returns true if every server signature has a local key and every local key is accepted by the server.
+func (s *SignatureReport) OK() bool {
+	return len(s.Missing) == 0 && len(s.Unexpected) == 0
+}
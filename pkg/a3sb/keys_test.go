@@ -0,0 +1,83 @@
+package a3sb
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeBiKey builds a minimal .bikey with 1024-bit RSA public key blob.
+func makeBiKey(name string) []byte {
+	const bits = 1024
+
+	blob := []byte{0x06, 0x02, 0x00, 0x00, 0x00, 0x24, 0x00, 0x00}
+	blob = append(blob, bikeyMagic...)
+	blob = binary.LittleEndian.AppendUint32(blob, bits)
+	blob = binary.LittleEndian.AppendUint32(blob, 65537)
+	blob = append(blob, make([]byte, bits/8)...)
+
+	data := append([]byte(name), 0)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(blob)))
+
+	return append(data, blob...)
+}
+
+func TestParseBiKey(t *testing.T) {
+	key, err := ParseBiKey(makeBiKey("cba_v3.15.8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Name != "cba_v3.15.8" || key.Bits != 1024 {
+		t.Errorf("ParseBiKey() = %+v", key)
+	}
+
+	broken := makeBiKey("a3")
+	if _, err := ParseBiKey(broken[:len(broken)-1]); !errors.Is(err, ErrBiKey) {
+		t.Errorf("truncated key error = %v", err)
+	}
+	if _, err := ParseBiKey([]byte("no terminator")); !errors.Is(err, ErrBiKey) {
+		t.Errorf("missing name terminator error = %v", err)
+	}
+}
+
+func TestCompareSignatures(t *testing.T) {
+	dir := t.TempDir()
+	for file, name := range map[string]string{
+		"a3.bikey":      "a3",
+		"CBA.bikey":     "cba_v3.15.8",
+		"old_mod.BIKEY": "old_mod",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, file), makeBiKey(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := ScanKeys(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("ScanKeys() found %d keys, want 3", len(keys))
+	}
+
+	rules := &Rules{Signatures: []string{"A3", "cba_v3.15.8", "ace_3.16.0"}}
+	report := rules.CompareSignatures(keys)
+
+	if len(report.Present) != 2 {
+		t.Errorf("Present = %v", report.Present)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "ace_3.16.0" {
+		t.Errorf("Missing = %v", report.Missing)
+	}
+	if len(report.Unexpected) != 1 || report.Unexpected[0].Name != "old_mod" {
+		t.Errorf("Unexpected = %+v", report.Unexpected)
+	}
+	if report.OK() {
+		t.Error("OK() = true, want false")
+	}
+}
@@ -0,0 +1,15 @@
+package a3sb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/woozymasta/a2s/internal/bread"
+)
+
+func TestReadModsLimit(t *testing.T) {
+	rules := &Rules{}
+	if err := rules.readMods(bread.NewReader([]byte{3}), 2); !errors.Is(err, ErrModCountLimit) {
+		t.Errorf("err = %v, want ErrModCountLimit", err)
+	}
+}
@@ -2,6 +2,7 @@ package a3sb
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/woozymasta/a2s/internal/bread"
 )
@@ -13,23 +14,24 @@ type Mod struct {
 	Hash uint32 `json:"hash,omitempty"` // Mod short hash
 }
 
-// arma3CreatorDLC is a map of Arma 3 creator DLC stored in mods byte block
-var arma3CreatorDLC = map[uint64]string{
-	1042220: "Creator DLC: Global Mobilization - Cold War Germany",
-	1175380: "Creator DLC: Spearhead 1944",
-	1227700: "Creator DLC: S.O.G. Prairie Fire",
-	1294440: "Creator DLC: CSLA Iron Curtain",
-	1681170: "Creator DLC: Western Sahara",
-	2647760: "Creator DLC: Reaction Forces",
-	2647830: "Creator DLC: Expeditionary Forces",
+// DisplayName returns the mod name, or its Workshop ID if the server did not send a name.
+func (m Mod) DisplayName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+
+	return strconv.FormatUint(m.ID, 10)
 }
 
-// readMods parses mods and creator DLC from A3SBP.
-func (r *Rules) readMods(reader *bread.Reader) error {
+// readMods parses mods and creator DLC from A3SBP, at most maxMods entries.
+func (r *Rules) readMods(reader *bread.Reader, maxMods int) error {
 	modCount, err := reader.Byte()
 	if err != nil {
 		return fmt.Errorf("mod count: %w", err)
 	}
+	if int(modCount) > maxMods {
+		return fmt.Errorf("%w: %d > %d", ErrModCountLimit, modCount, maxMods)
+	}
 	if modCount == 0 {
 		return nil
 	}
@@ -78,7 +80,7 @@ func (r *Rules) readMods(reader *bread.Reader) error {
 				return fmt.Errorf("mod %d id length: %w", i, err)
 			}
 			creatorDLC.ID = uint64(id)
-			creatorDLC.Name = arma3CreatorDLC[creatorDLC.ID]
+			creatorDLC.Name = r.catalog.Game(r.id).CreatorDLCName(creatorDLC.ID)
 			r.CreatorDLC = append(r.CreatorDLC, creatorDLC)
 			continue
 
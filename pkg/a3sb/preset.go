@@ -0,0 +1,169 @@
+package a3sb
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const (
+	// WorkshopURL is the Steam Workshop item page prefix, append the mod ID.
+	WorkshopURL = "https://steamcommunity.com/sharedfiles/filedetails/?id="
+
+	// StoreURL is the Steam Store application page prefix, append the DLC AppID.
+	StoreURL = "https://store.steampowered.com/app/"
+)
+
+// presetTemplate is the Arma 3 Launcher preset document layout.
+// The launcher only reads the meta tags and rows marked with data-type attributes,
+// the styling is kept close to what the launcher exports itself.
+var presetTemplate = template.Must(template.New("preset").Parse(`<?xml version="1.0" encoding="utf-8"?>
+<html>
+  <!--Created by Arma 3 Launcher: https://arma3.com-->
+  <head>
+    <meta name="arma:Type" content="preset" />
+    <meta name="arma:PresetName" content="{{ html .Name }}" />
+    <meta name="generator" content="Arma 3 Launcher - https://arma3.com" />
+    <title>Arma 3</title>
+    <style>
+body { margin: 0; padding: 0; color: #fff; background: #000; }
+body, th, td { font: 95%/1.3 Roboto, Segoe UI, Tahoma, Arial, Helvetica, sans-serif; }
+td { padding: 3px 30px 3px 0; }
+h1 { padding: 20px 20px 0 20px; color: white; font-weight: 200; font-family: segoe ui; font-size: 3em; margin: 0; }
+em { font-variant: italic; color:silver; }
+.before-list { padding: 5px 20px 10px 20px; }
+.mod-list { background: #222222; padding: 20px; }
+.dlc-list { background: #222222; padding: 20px; }
+.footer { padding: 20px; color:gray; }
+.whups { color:gray; }
+a { color: #D18F21; text-decoration: underline; }
+a:hover { color:#F1AF41; text-decoration: none; }
+.from-steam { color: #449EBD; }
+.from-local { color: gray; }
+    </style>
+  </head>
+  <body>
+    <h1>Arma 3  - Preset <strong>{{ html .Name }}</strong></h1>
+    <p class="before-list">
+      <em>To import this preset, drag this file onto the Launcher window. Or click the MODS tab, then PRESET in the top right, then IMPORT at the bottom, and finally select this file.</em>
+    </p>
+    <div class="mod-list">
+      <table>
+{{- range .Mods }}
+        <tr data-type="ModContainer">
+          <td data-type="DisplayName">{{ html .Name }}</td>
+          <td>
+            <span class="from-steam">Steam</span>
+          </td>
+          <td>
+            <a href="{{ html .URL }}" data-type="Link">{{ html .URL }}</a>
+          </td>
+        </tr>
+{{- end }}
+      </table>
+    </div>
+    <div class="dlc-list">
+      <table>
+{{- range .DLC }}
+        <tr data-type="DlcContainer">
+          <td data-type="DisplayName">{{ html .Name }}</td>
+          <td>
+            <a href="{{ html .URL }}" data-type="Link">{{ html .URL }}</a>
+          </td>
+        </tr>
+{{- end }}
+      </table>
+    </div>
+    <div class="footer">
+      <span>Created by Arma 3 Launcher by Bohemia Interactive.</span>
+    </div>
+  </body>
+</html>
+`))
+
+// presetItem is a single mod or DLC row of the preset document.
+type presetItem struct {
+	Name string
+	URL  string
+}
+
+// WritePreset writes mods, DLC and Creator DLC as an Arma 3 Launcher preset HTML document.
+// DLC without known Steam AppID are skipped, mods without a name use the Workshop ID instead.
+func (r *Rules) WritePreset(w io.Writer, name string) error {
+	data := struct {
+		Name string
+		Mods []presetItem
+		DLC  []presetItem
+	}{
+		Name: name,
+		Mods: make([]presetItem, 0, len(r.Mods)),
+		DLC:  make([]presetItem, 0, len(r.DLC)+len(r.CreatorDLC)),
+	}
+
+	for _, mod := range r.Mods {
+		data.Mods = append(data.Mods, presetItem{
+			Name: mod.DisplayName(),
+			URL:  WorkshopURL + strconv.FormatUint(mod.ID, 10),
+		})
+	}
+
+	for _, list := range [][]DLCInfo{r.DLC, r.CreatorDLC} {
+		for _, dlc := range list {
+			if dlc.ID == 0 {
+				continue
+			}
+			data.DLC = append(data.DLC, presetItem{
+				Name: dlc.Name,
+				URL:  StoreURL + strconv.FormatUint(dlc.ID, 10),
+			})
+		}
+	}
+
+	return presetTemplate.Execute(w, data)
+}
+
+// LaunchParameter returns the "-mod=" launch parameter for the server mods,
+// e.g. "-mod=@CF;@Community-Online-Tools". The parameter is wrapped in double quotes
+// if a mod name contains whitespace, e.g. "\"-mod=@CF;@Dabs Framework\"".
+// Names with ";" or double quotes can't be passed to the game and return ErrModName.
+// Returns empty string if the server has no mods.
+func (r *Rules) LaunchParameter() (string, error) {
+	if len(r.Mods) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("-mod=")
+	for i, mod := range r.Mods {
+		name := mod.DisplayName()
+		if strings.ContainsAny(name, `;"`) {
+			return "", fmt.Errorf("%w: %q", ErrModName, name)
+		}
+
+		if i > 0 {
+			sb.WriteByte(';')
+		}
+		sb.WriteByte('@')
+		sb.WriteString(name)
+	}
+
+	param := sb.String()
+	if strings.ContainsFunc(param, unicode.IsSpace) {
+		param = `"` + param + `"`
+	}
+
+	return param, nil
+}
+
+// WorkshopIDs returns Steam Workshop IDs of the server mods in response order.
+func (r *Rules) WorkshopIDs() []uint64 {
+	ids := make([]uint64, 0, len(r.Mods))
+	for _, mod := range r.Mods {
+		ids = append(ids, mod.ID)
+	}
+
+	return ids
+}
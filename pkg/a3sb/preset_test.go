@@ -0,0 +1,87 @@
+package a3sb
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testModsRules() *Rules {
+	return &Rules{
+		Mods: []Mod{
+			{Name: "CF", ID: 1559212036},
+			{Name: "Dabs Framework", ID: 2545327648},
+			{ID: 1564026768},
+		},
+		DLC: []DLCInfo{
+			{Name: "Livonia", ID: 1151700},
+			{Name: "Unknown DLC 16"},
+		},
+		CreatorDLC: []DLCInfo{
+			{Name: "Creator DLC: Western Sahara", ID: 1681170},
+		},
+	}
+}
+
+func TestLaunchParameter(t *testing.T) {
+	tests := []struct {
+		want error
+		name string
+		mods []Mod
+		out  string
+	}{
+		{name: "spaces quoted", mods: testModsRules().Mods, out: `"-mod=@CF;@Dabs Framework;@1564026768"`},
+		{name: "plain", mods: []Mod{{Name: "CF"}, {Name: "Community-Online-Tools"}}, out: "-mod=@CF;@Community-Online-Tools"},
+		{name: "no mods", out: ""},
+		{name: "separator in name", mods: []Mod{{Name: "CF"}, {Name: "A;B"}}, want: ErrModName},
+		{name: "quote in name", mods: []Mod{{Name: `The "Mod"`}}, want: ErrModName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&Rules{Mods: tt.mods}).LaunchParameter()
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if got != tt.out {
+				t.Errorf("LaunchParameter() = %s, want %s", got, tt.out)
+			}
+		})
+	}
+}
+
+func TestWorkshopIDs(t *testing.T) {
+	ids := testModsRules().WorkshopIDs()
+	if len(ids) != 3 || ids[0] != 1559212036 || ids[2] != 1564026768 {
+		t.Errorf("WorkshopIDs() = %v", ids)
+	}
+}
+
+func TestWritePreset(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testModsRules().WritePreset(&buf, "Server <EU>"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`<meta name="arma:PresetName" content="Server &lt;EU&gt;" />`,
+		`<!--Created by Arma 3 Launcher: https://arma3.com-->`,
+		`<td data-type="DisplayName">Dabs Framework</td>`,
+		WorkshopURL + "2545327648",
+		StoreURL + "1151700",
+		StoreURL + "1681170",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("preset does not contain %q", want)
+		}
+	}
+
+	if n := strings.Count(out, `data-type="ModContainer"`); n != 3 {
+		t.Errorf("preset has %d mods, want 3", n)
+	}
+	if n := strings.Count(out, `data-type="DlcContainer"`); n != 2 {
+		t.Errorf("preset has %d DLC, want 2 (unknown DLC skipped)", n)
+	}
+}
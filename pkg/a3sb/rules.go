@@ -24,6 +24,7 @@ type Rules struct {
 	CreatorDLC      []DLCInfo         `json:"creator_dlc,omitempty"`      // List of information about Creator DLC (Arma 3 only)
 	Mods            []Mod             `json:"mods,omitempty"`             // List of information about modifications
 	Signatures      []string          `json:"signatures,omitempty"`       // List of signatures
+	catalog         *Catalog          ``                                  // DLC catalog
 	id              uint64            ``                                  // Steam AppID
 	Language        types.ServerLang  `json:"language,omitempty"`         // DayZ Server Language [DayZ]
 	AllowedBuild    uint16            `json:"allowed_build,omitempty"`    // Allowed client build for connect [DayZ]
@@ -48,25 +49,33 @@ func (c *Client) GetRulesDayZ() (*Rules, error) {
 
 // GetRules parses A2S_RULES response using A3SB for Arma 3 and DayZ.
 func (c *Client) GetRules(game uint64) (*Rules, error) {
-	if c.BufferSize == a2s.DefaultBufferSize {
-		c.SetBufferSize(DefaultRulesBufferSize)
-	}
+	defer c.rulesBuffer()()
 
 	data, _, _, err := c.Get(a2s.RulesRequest)
 	if err != nil {
 		return nil, err
 	}
 
+	return parseRules(data, game, c.catalog(), c.Limits)
+}
+
+// parseRules parses A2S_RULES response data with A3SB pages within limits.
+func parseRules(data []byte, game uint64, catalog *Catalog, limits a2s.Limits) (*Rules, error) {
+	limits = limits.WithDefaults()
 	reader := bread.NewReader(data)
+	reader.SetMaxString(limits.MaxStringLength)
 
 	count, err := reader.Uint16()
 	if err != nil {
-		return nil, fmt.Errorf("%w count: 0x%X", ErrRules, data[:4])
+		return nil, fmt.Errorf("%w count: 0x%X", ErrRules, data[:min(4, len(data))])
+	}
+	if err := limits.CheckRules(int(count)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRules, err)
 	}
 
 	var a3sb []byte
 	var rawRules map[string]string
-	rules := &Rules{id: game, stats: [4]byte{data[1], 0, 0, 0}}
+	rules := &Rules{id: game, catalog: catalog, stats: [4]byte{data[1], 0, 0, 0}}
 
 	for i := 0; i < int(count); i++ {
 		key, err := reader.BytesPage()
@@ -114,7 +123,7 @@ func (c *Client) GetRules(game uint64) (*Rules, error) {
 		return nil, ErrRulesDataRemains
 	}
 
-	if err := rules.readA3SB(a3sb); err != nil {
+	if err := rules.readA3SB(a3sb, limits); err != nil {
 		return nil, err
 	}
 
@@ -125,9 +134,10 @@ func (c *Client) GetRules(game uint64) (*Rules, error) {
 	return rules, nil
 }
 
-// readA3SB parses Arma 3 Server Browser Protocol data.
-func (r *Rules) readA3SB(data []byte) error {
+// readA3SB parses Arma 3 Server Browser Protocol data within limits.
+func (r *Rules) readA3SB(data []byte, limits a2s.Limits) error {
 	reader := bread.NewReader(data)
+	reader.SetMaxString(limits.MaxStringLength)
 	var err error
 
 	if err := r.readVersion(reader); err != nil {
@@ -153,7 +163,7 @@ func (r *Rules) readA3SB(data []byte) error {
 		}
 	}
 
-	if err := r.readMods(reader); err != nil {
+	if err := r.readMods(reader, limits.MaxMods); err != nil {
 		return fmt.Errorf("%w: %w", ErrMod, err)
 	}
 
@@ -0,0 +1,319 @@
+package a3sb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LocalMod contains mod information read from a local Steam Workshop install.
+type LocalMod struct {
+	Path        string `json:"path"`                   // Mod directory
+	Name        string `json:"name,omitempty"`         // Mod name from meta.cpp
+	ID          uint64 `json:"id"`                     // Steam Workshop ID from meta.cpp or directory name
+	Timestamp   int64  `json:"timestamp,omitempty"`    // Publish timestamp from meta.cpp
+	NeedsUpdate bool   `json:"needs_update,omitempty"` // Steam reports a newer version than installed
+}
+
+// GameVersion is a local game client version, e.g. 1.27.159674 for DayZ.
+type GameVersion struct {
+	Version uint16 `json:"version"`         // Major and minor parts as reported in requiredVersion (1.27 -> 127)
+	Build   uint32 `json:"build,omitempty"` // Build number
+}
+
+// ModReport is the result of comparing server mods against a local install.
+type ModReport struct {
+	Version         *GameVersion `json:"local_version,omitempty"`    // Local game version if supplied
+	Missing         []Mod        `json:"missing,omitempty"`          // Required by server, not installed
+	Outdated        []LocalMod   `json:"outdated,omitempty"`         // Installed, but Steam has a newer version
+	Extra           []LocalMod   `json:"extra,omitempty"`            // Installed, not required by server
+	Matched         []LocalMod   `json:"matched,omitempty"`          // Installed and required by server
+	RequiredVersion uint16       `json:"required_version,omitempty"` // Server required client version
+	RequiredBuild   uint16       `json:"required_build,omitempty"`   // Server required client build
+	VersionOK       bool         `json:"version_ok"`                 // Local version satisfies requiredVersion
+	BuildOK         bool         `json:"build_ok"`                   // Local build satisfies requiredBuild, true if the build is unknown
+}
+
+// ParseGameVersion parses a game version string such as "1.27", "1.27.159674" or "2.18.152049".
+func ParseGameVersion(s string) (GameVersion, error) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return GameVersion{}, fmt.Errorf("invalid game version %q, expected major.minor[.build]", s)
+	}
+
+	major, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil {
+		return GameVersion{}, fmt.Errorf("invalid game version %q: %w", s, err)
+	}
+	minor, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil || minor > 99 {
+		return GameVersion{}, fmt.Errorf("invalid game version %q: minor must be 0-99", s)
+	}
+
+	v := GameVersion{Version: uint16(major*100 + minor)} // #nosec G115
+
+	if len(parts) == 3 {
+		build, err := strconv.ParseUint(parts[2], 10, 32)
+		if err != nil {
+			return GameVersion{}, fmt.Errorf("invalid game build %q: %w", s, err)
+		}
+		v.Build = uint32(build) // #nosec G115
+	}
+
+	return v, nil
+}
+
+// ScanWorkshop reads installed mods from a Steam Workshop content directory,
+// e.g. steamapps/workshop/content/221100. Every subdirectory with a meta.cpp is a mod.
+// If the sibling appworkshop_<appid>.acf manifest exists, it is used to mark mods with pending updates.
+// Mods with malformed meta.cpp are skipped, the other mods are returned together with
+// their joined ErrWorkshopMeta errors.
+func ScanWorkshop(dir string) ([]LocalMod, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var skipped []error
+	mods := make([]LocalMod, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		mod, err := ReadModMeta(filepath.Join(dir, entry.Name()))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue
+		case errors.Is(err, ErrWorkshopMeta):
+			skipped = append(skipped, err)
+			continue
+		case err != nil:
+			return nil, err
+		}
+		mods = append(mods, mod)
+	}
+
+	appID := filepath.Base(filepath.Clean(dir))
+	manifest := filepath.Join(dir, "..", "..", "appworkshop_"+appID+".acf")
+	if pending, err := readWorkshopUpdates(manifest); err == nil {
+		for i := range mods {
+			mods[i].NeedsUpdate = pending[mods[i].ID]
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return mods, errors.Join(skipped...)
+}
+
+// ReadModMeta reads meta.cpp from a mod directory. If publishedid is missing or zero,
+// a numeric directory name is used as Workshop ID.
+func ReadModMeta(dir string) (LocalMod, error) {
+	file, err := os.Open(filepath.Join(dir, "meta.cpp")) // #nosec G304
+	if err != nil {
+		return LocalMod{}, err
+	}
+	defer func() { _ = file.Close() }()
+
+	mod := LocalMod{Path: dir}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.Trim(strings.TrimSpace(value), `;"`)
+
+		switch key {
+		case "publishedid":
+			if mod.ID, err = strconv.ParseUint(value, 10, 64); err != nil {
+				return LocalMod{}, fmt.Errorf("%w: %s publishedid: %w", ErrWorkshopMeta, dir, err)
+			}
+		case "name":
+			mod.Name = value
+		case "timestamp":
+			mod.Timestamp, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return LocalMod{}, fmt.Errorf("%w: %s: %w", ErrWorkshopMeta, dir, err)
+	}
+
+	if mod.ID == 0 {
+		mod.ID, _ = strconv.ParseUint(filepath.Base(dir), 10, 64)
+	}
+
+	return mod, nil
+}
+
+// CompareMods matches server mods against locally installed mods by Workshop ID.
+// If version is not nil, requiredVersion and requiredBuild are checked against it,
+// requiredBuild only if the local build is known.
+func (r *Rules) CompareMods(local []LocalMod, version *GameVersion) *ModReport {
+	report := &ModReport{
+		Version:         version,
+		RequiredVersion: r.RequiredVersion,
+		RequiredBuild:   r.RequiredBuild,
+		VersionOK:       true,
+		BuildOK:         true,
+	}
+
+	installed := make(map[uint64]LocalMod, len(local))
+	for _, mod := range local {
+		installed[mod.ID] = mod
+	}
+
+	required := make(map[uint64]struct{}, len(r.Mods))
+	for _, mod := range r.Mods {
+		required[mod.ID] = struct{}{}
+
+		localMod, ok := installed[mod.ID]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, mod)
+		case localMod.NeedsUpdate:
+			report.Outdated = append(report.Outdated, localMod)
+		default:
+			report.Matched = append(report.Matched, localMod)
+		}
+	}
+
+	for _, mod := range local {
+		if _, ok := required[mod.ID]; !ok {
+			report.Extra = append(report.Extra, mod)
+		}
+	}
+
+	if version != nil {
+		report.VersionOK = r.RequiredVersion == 0 || version.Version >= r.RequiredVersion
+		report.BuildOK = r.RequiredBuild == 0 || version.Build == 0 || version.Build >= uint32(r.RequiredBuild)
+	}
+
+	return report
+}
+
+// CanJoin returns true if no mods are missing or outdated and the local version is sufficient.
+func (m *ModReport) CanJoin() bool {
+	return len(m.Missing) == 0 && len(m.Outdated) == 0 && m.VersionOK && m.BuildOK
+}
+
+// readWorkshopUpdates reads Workshop items with pending updates from appworkshop_<appid>.acf.
+// An item needs update when its installed manifest differs from the latest known manifest.
+func readWorkshopUpdates(path string) (map[uint64]bool, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseVDF(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	details := root.child("AppWorkshop").child("WorkshopItemDetails")
+	pending := make(map[uint64]bool, len(details))
+	for key, value := range details {
+		item, ok := value.(vdfNode)
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		manifest, _ := item["manifest"].(string)
+		latest, _ := item["latest_manifest"].(string)
+		updated, _ := item["timeupdated"].(string)
+		latestUpdated, _ := item["latest_timeupdated"].(string)
+		updatedTime, _ := strconv.ParseInt(updated, 10, 64)
+		latestTime, _ := strconv.ParseInt(latestUpdated, 10, 64)
+
+		pending[id] = (latest != "" && latest != manifest) || latestTime > updatedTime
+	}
+
+	return pending, nil
+}
+
+// vdfNode is a parsed Valve KeyValues object, values are string or vdfNode.
+type vdfNode map[string]any
+
+// child returns nested node by key or nil.
+func (n vdfNode) child(key string) vdfNode {
+	if n == nil {
+		return nil
+	}
+	child, _ := n[key].(vdfNode)
+	return child
+}
+
+// parseVDF parses text Valve KeyValues (VDF/ACF) format.
+func parseVDF(data string) (vdfNode, error) {
+	var (
+		stack = []vdfNode{{}}
+		key   string
+		inKey = true
+	)
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+
+		case c == '{':
+			if inKey {
+				return nil, errors.New("vdf: unexpected '{'")
+			}
+			node := vdfNode{}
+			stack[len(stack)-1][key] = node
+			stack = append(stack, node)
+			inKey = true
+
+		case c == '}':
+			if !inKey || len(stack) == 1 {
+				return nil, errors.New("vdf: unexpected '}'")
+			}
+			stack = stack[:len(stack)-1]
+
+		case c == '"':
+			var sb strings.Builder
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' && i+1 < len(data) {
+					i++
+				}
+				sb.WriteByte(data[i])
+			}
+			if i >= len(data) {
+				return nil, errors.New("vdf: unterminated string")
+			}
+
+			if inKey {
+				key = sb.String()
+			} else {
+				stack[len(stack)-1][key] = sb.String()
+			}
+			inKey = !inKey
+
+		default:
+			return nil, fmt.Errorf("vdf: unexpected character %q", c)
+		}
+	}
+
+	if len(stack) != 1 || !inKey {
+		return nil, errors.New("vdf: unexpected end of data")
+	}
+
+	return stack[0], nil
+}
@@ -0,0 +1,181 @@
+package a3sb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestWorkshop creates steamapps/workshop/content/221100 layout with two mods and manifest.
+func writeTestWorkshop(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	content := filepath.Join(root, "workshop", "content", "221100")
+
+	metas := map[string]string{
+		"1559212036": "protocol = 1;\npublishedid = 1559212036;\nname = \"CF\";\ntimestamp = 5249766083486434557;\n",
+		"1564026768": "protocol = 1;\npublishedid = 0;\nname = \"Community-Online-Tools\";\n",
+		"1111111111": "protocol = 1;\npublishedid = 1111111111;\nname = \"Extra Mod\";\n",
+	}
+	for dir, meta := range metas {
+		if err := os.MkdirAll(filepath.Join(content, dir), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(content, dir, "meta.cpp"), []byte(meta), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(content, "not-a-mod"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	acf := `"AppWorkshop"
+{
+	"appid"		"221100"
+	"WorkshopItemsInstalled"
+	{
+		"1559212036"
+		{
+			"manifest"		"100"
+		}
+	}
+	"WorkshopItemDetails"
+	{
+		"1559212036"
+		{
+			"manifest"		"100"
+			"timeupdated"		"1700000000"
+			"latest_timeupdated"		"1700000000"
+			"latest_manifest"		"100"
+		}
+		"1564026768"
+		{
+			"manifest"		"200"
+			"timeupdated"		"1700000000"
+			"latest_timeupdated"		"1700000500"
+			"latest_manifest"		"201"
+		}
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(root, "workshop", "appworkshop_221100.acf"), []byte(acf), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return content
+}
+
+func TestScanWorkshop(t *testing.T) {
+	mods, err := ScanWorkshop(writeTestWorkshop(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mods) != 3 {
+		t.Fatalf("ScanWorkshop() found %d mods, want 3", len(mods))
+	}
+
+	byID := make(map[uint64]LocalMod, len(mods))
+	for _, mod := range mods {
+		byID[mod.ID] = mod
+	}
+
+	if cot, ok := byID[1564026768]; !ok || cot.Name != "Community-Online-Tools" || !cot.NeedsUpdate {
+		t.Errorf("mod with zero publishedid not resolved from directory name: %+v", cot)
+	}
+	if cf := byID[1559212036]; cf.NeedsUpdate || cf.Timestamp == 0 {
+		t.Errorf("unexpected CF mod state: %+v", cf)
+	}
+}
+
+func TestScanWorkshopMalformedMeta(t *testing.T) {
+	content := writeTestWorkshop(t)
+	broken := filepath.Join(content, "2222222222")
+	if err := os.MkdirAll(broken, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(broken, "meta.cpp"), []byte("publishedid = abc;\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mods, err := ScanWorkshop(content)
+	if !errors.Is(err, ErrWorkshopMeta) || !strings.Contains(err.Error(), broken) {
+		t.Errorf("ScanWorkshop() error = %v, want ErrWorkshopMeta of %s", err, broken)
+	}
+	if len(mods) != 3 {
+		t.Errorf("ScanWorkshop() found %d mods, want 3 without the malformed one", len(mods))
+	}
+}
+
+func TestCompareMods(t *testing.T) {
+	local, err := ScanWorkshop(writeTestWorkshop(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := &Rules{
+		Mods: []Mod{
+			{Name: "CF", ID: 1559212036},
+			{Name: "Community-Online-Tools", ID: 1564026768},
+			{Name: "Missing", ID: 2222222222},
+		},
+		RequiredVersion: 127,
+	}
+
+	version, err := ParseGameVersion("1.26.158593")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := rules.CompareMods(local, &version)
+	if len(report.Missing) != 1 || report.Missing[0].ID != 2222222222 {
+		t.Errorf("Missing = %+v", report.Missing)
+	}
+	if len(report.Outdated) != 1 || report.Outdated[0].ID != 1564026768 {
+		t.Errorf("Outdated = %+v", report.Outdated)
+	}
+	if len(report.Extra) != 1 || report.Extra[0].ID != 1111111111 {
+		t.Errorf("Extra = %+v", report.Extra)
+	}
+	if len(report.Matched) != 1 {
+		t.Errorf("Matched = %+v", report.Matched)
+	}
+	if report.VersionOK || !report.BuildOK || report.CanJoin() {
+		t.Errorf("version check: %+v", report)
+	}
+
+	// Build is not checked without local build
+	rules.Mods, rules.RequiredVersion, rules.RequiredBuild = nil, 127, 59674
+	for in, buildOK := range map[string]bool{"1.27": true, "1.27.59000": false, "1.27.59674": true} {
+		version, err := ParseGameVersion(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report := rules.CompareMods(nil, &version); report.BuildOK != buildOK || report.CanJoin() != buildOK {
+			t.Errorf("--game-version %s: BuildOK = %t, want %t", in, report.BuildOK, buildOK)
+		}
+	}
+}
+
+func TestParseGameVersion(t *testing.T) {
+	cases := map[string]GameVersion{
+		"1.27":        {Version: 127},
+		"1.27.159674": {Version: 127, Build: 159674},
+		"2.18.152049": {Version: 218, Build: 152049},
+	}
+	for in, want := range cases {
+		got, err := ParseGameVersion(in)
+		if err != nil || got != want {
+			t.Errorf("ParseGameVersion(%q) = %+v, %v; want %+v", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "1", "1.x", "1.2.3.4", "1.100"} {
+		if _, err := ParseGameVersion(in); err == nil {
+			t.Errorf("ParseGameVersion(%q) expected error", in)
+		}
+	}
+}
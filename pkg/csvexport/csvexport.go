@@ -0,0 +1,253 @@
+// Package csvexport writes server info, players and rules as CSV or TSV for spreadsheets.
+//
+// Columns have a stable order and nested values are flattened: Arma 3 and DayZ keywords
+// become "keywords.*" columns of the info row, A3SB mods, DLC and signatures become
+// repeated rows of the rules sheet.
+package csvexport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/keywords"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+// InfoColumns are the leading columns of the info sheet, parsed keyword columns follow them.
+var InfoColumns = []string{
+	"name", "map", "folder", "game", "version", "app_id", "players", "max_players", "bots",
+	"type", "environment", "password", "vac", "protocol", "format", "port", "steam_id", "ping_ms", "keywords",
+}
+
+// PlayerColumns are the columns of the players sheet.
+var PlayerColumns = []string{"index", "name", "score", "duration_s"}
+
+// RuleColumns are the columns of the standard rules sheet.
+var RuleColumns = []string{"rule", "value"}
+
+// A3SBColumns are the columns of the A3SB rules sheet. Section is one of "rule", "dlc",
+// "creator_dlc", "mod" or "signature".
+var A3SBColumns = []string{"section", "name", "id", "hash", "value"}
+
+// Writer writes sheets as comma or tab separated values.
+type Writer struct {
+	w *csv.Writer
+}
+
+// NewWriter returns a writer of comma separated values.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: csv.NewWriter(w)}
+}
+
+// NewTSVWriter returns a writer of tab separated values.
+func NewTSVWriter(w io.Writer) *Writer {
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	return &Writer{w: cw}
+}
+
+// WriteInfo writes a header and one row per server. Keyword columns of Arma 3 and then DayZ
+// follow InfoColumns if any of the servers runs the game, cells of other servers stay empty.
+func (w *Writer) WriteInfo(infos ...*a2s.Info) error {
+	var arma3, dayz bool
+	for _, info := range infos {
+		switch info.ID {
+		case appid.Arma3.Uint64():
+			arma3 = true
+		case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
+			dayz = true
+		}
+	}
+
+	header := append([]string(nil), InfoColumns...)
+	if arma3 {
+		header = append(header, fieldNames("keywords.", keywords.Arma3{})...)
+	}
+	if dayz {
+		header = append(header, fieldNames("keywords.", keywords.DayZ{})...)
+	}
+
+	rows := make([][]string, 0, len(infos))
+	for _, info := range infos {
+		row := []string{
+			info.Name,
+			info.Map,
+			info.Folder,
+			info.Game,
+			info.Version,
+			strconv.FormatUint(info.ID, 10),
+			strconv.Itoa(int(info.Players)),
+			strconv.Itoa(int(info.MaxPlayers)),
+			strconv.Itoa(int(info.Bots)),
+			info.ServerType.String(),
+			info.Environment.String(),
+			strconv.FormatBool(info.Visibility),
+			strconv.FormatBool(info.VAC),
+			strconv.Itoa(int(info.Protocol)),
+			info.Format.String(),
+			strconv.Itoa(int(info.Port)),
+			strconv.FormatUint(info.SteamID, 10),
+			strconv.FormatInt(info.Ping.Milliseconds(), 10),
+			strings.Join(info.Keywords, ","),
+		}
+
+		isArma3 := info.ID == appid.Arma3.Uint64()
+		isDayZ := info.ID == appid.DayZ.Uint64() || info.ID == appid.DayZExp.Uint64()
+
+		if arma3 {
+			row = append(row, fieldValues(*keywords.ParseArma3(info.Keywords), isArma3)...)
+		}
+		if dayz {
+			row = append(row, fieldValues(*keywords.ParseDayZ(info.Keywords), isDayZ)...)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return w.write(header, rows)
+}
+
+// WritePlayers writes a header and one row per player, duration in whole seconds.
+func (w *Writer) WritePlayers(players []a2s.Player) error {
+	rows := make([][]string, 0, len(players))
+	for _, player := range players {
+		rows = append(rows, []string{
+			strconv.Itoa(int(player.Index)),
+			player.Name,
+			strconv.FormatUint(uint64(player.Score), 10),
+			strconv.FormatInt(int64(player.Duration/time.Second), 10),
+		})
+	}
+
+	return w.write(PlayerColumns, rows)
+}
+
+// WriteRules writes a header and one row per rule sorted by name.
+func (w *Writer) WriteRules(rules map[string]string) error {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name, rules[name]})
+	}
+
+	return w.write(RuleColumns, rows)
+}
+
+// WriteA3SBRules writes a header and A3SB rules as rows of sections: scalar rules, difficulty
+// and extra rules first, then DLC, Creator DLC, mods and signatures in server order.
+func (w *Writer) WriteA3SBRules(rules *a3sb.Rules) error {
+	var rows [][]string
+	rule := func(name, value string) {
+		rows = append(rows, []string{"rule", name, "", "", value})
+	}
+
+	rule("version", strconv.Itoa(int(rules.Version)))
+	rule("required_version", strconv.Itoa(int(rules.RequiredVersion)))
+	rule("required_build", strconv.Itoa(int(rules.RequiredBuild)))
+	rule("allowed_build", strconv.Itoa(int(rules.AllowedBuild)))
+	rule("client_port", strconv.Itoa(int(rules.ClientPort)))
+	rule("time_left", strconv.Itoa(int(rules.TimeLeft)))
+	rule("dedicated", strconv.FormatBool(rules.Dedicated))
+	rule("island", rules.Island)
+	rule("platform", rules.Platform)
+	rule("language", rules.Language.String())
+	rule("description", rules.Description)
+
+	if rules.Difficulty != nil {
+		names := fieldNames("difficulty.", *rules.Difficulty)
+		for i, value := range fieldValues(*rules.Difficulty, true) {
+			rule(names[i], value)
+		}
+	}
+
+	extra := make([]string, 0, len(rules.ExtraRules))
+	for name := range rules.ExtraRules {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		rule("extra."+name, rules.ExtraRules[name])
+	}
+
+	for _, dlc := range rules.DLC {
+		rows = append(rows, []string{"dlc", dlc.Name, strconv.FormatUint(dlc.ID, 10), hash(dlc.Hash), ""})
+	}
+	for _, dlc := range rules.CreatorDLC {
+		rows = append(rows, []string{"creator_dlc", dlc.Name, strconv.FormatUint(dlc.ID, 10), hash(dlc.Hash), ""})
+	}
+	for _, mod := range rules.Mods {
+		rows = append(rows, []string{"mod", mod.DisplayName(), strconv.FormatUint(mod.ID, 10), hash(mod.Hash), ""})
+	}
+	for _, signature := range rules.Signatures {
+		rows = append(rows, []string{"signature", signature, "", "", ""})
+	}
+
+	return w.write(A3SBColumns, rows)
+}
+
+// write writes the header and rows and flushes the underlying writer.
+func (w *Writer) write(header []string, rows [][]string) error {
+	if err := w.w.Write(header); err != nil {
+		return err
+	}
+	if err := w.w.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return w.w.Error()
+}
+
+// hash formats a short hash as 8 hex digits.
+func hash(value uint32) string {
+	return fmt.Sprintf("%08x", value)
+}
+
+// fieldNames returns JSON names of struct fields in declaration order with prefix.
+func fieldNames(prefix string, v any) []string {
+	t := reflect.TypeOf(v)
+	names := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		names = append(names, prefix+name)
+	}
+
+	return names
+}
+
+// fieldValues returns struct field values in declaration order, slices joined with ";".
+// All values are empty if set is false.
+func fieldValues(v any, set bool) []string {
+	value := reflect.ValueOf(v)
+	values := make([]string, value.NumField())
+	if !set {
+		return values
+	}
+
+	for i := range value.NumField() {
+		field := value.Field(i)
+		if field.Kind() == reflect.Slice {
+			parts := make([]string, field.Len())
+			for j := range parts {
+				parts[j] = fmt.Sprint(field.Index(j).Interface())
+			}
+			values[i] = strings.Join(parts, ";")
+			continue
+		}
+		values[i] = fmt.Sprint(field.Interface())
+	}
+
+	return values
+}
@@ -0,0 +1,156 @@
+package csvexport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+func readAll(t *testing.T, data string, comma rune) [][]string {
+	t.Helper()
+
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	return records
+}
+
+func column(header []string, name string) int {
+	return slices.Index(header, name)
+}
+
+func TestWriteInfo(t *testing.T) {
+	infos := []*a2s.Info{
+		{Name: "Arma, \"quoted\"", Map: "Altis", ID: appid.Arma3.Uint64(), Players: 5, MaxPlayers: 64, Keywords: []string{"bt", "r218", "n0", "s1"}},
+		{Name: "DayZ", Map: "chernarusplus", ID: appid.DayZ.Uint64(), Keywords: []string{"battleye", "no3rd", "etm4.000000"}},
+		{Name: "TF2", Map: "ctf_2fort", ID: 440, Ping: 42 * time.Millisecond, Visibility: true},
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).WriteInfo(infos...); err != nil {
+		t.Fatalf("WriteInfo: %v", err)
+	}
+
+	records := readAll(t, buf.String(), ',')
+	if len(records) != 4 {
+		t.Fatalf("records = %d, want 4", len(records))
+	}
+
+	header := records[0]
+	if !slices.Equal(header[:len(InfoColumns)], InfoColumns) {
+		t.Errorf("header starts with %v, want %v", header[:len(InfoColumns)], InfoColumns)
+	}
+	for _, row := range records[1:] {
+		if len(row) != len(header) {
+			t.Errorf("row has %d cells, header %d", len(row), len(header))
+		}
+	}
+
+	if got := records[1][column(header, "name")]; got != infos[0].Name {
+		t.Errorf("name = %q, want %q", got, infos[0].Name)
+	}
+	if got := records[3][column(header, "ping_ms")]; got != "42" {
+		t.Errorf("ping_ms = %q, want 42", got)
+	}
+	if got := records[3][column(header, "password")]; got != "true" {
+		t.Errorf("password = %q, want true", got)
+	}
+
+	battleye := column(header, "keywords.battleye")
+	if battleye < 0 {
+		t.Fatalf("no keywords.battleye column in %v", header)
+	}
+	if got := records[1][battleye]; got != "true" {
+		t.Errorf("arma3 keywords.battleye = %q, want true", got)
+	}
+	if got := records[3][battleye]; got != "" {
+		t.Errorf("tf2 keywords.battleye = %q, want empty", got)
+	}
+	if got := records[2][column(header, "keywords.no3rd")]; got != "true" {
+		t.Errorf("dayz keywords.no3rd = %q, want true", got)
+	}
+}
+
+func TestWriteInfoStable(t *testing.T) {
+	info := &a2s.Info{Name: "TF2", ID: 440}
+
+	var first, second bytes.Buffer
+	if err := NewTSVWriter(&first).WriteInfo(info); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewTSVWriter(&second).WriteInfo(info); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.String() != second.String() {
+		t.Errorf("output differs:\n%s\n%s", first.String(), second.String())
+	}
+	if header := readAll(t, first.String(), '\t')[0]; !slices.Equal(header, InfoColumns) {
+		t.Errorf("header = %v, want %v", header, InfoColumns)
+	}
+}
+
+func TestWritePlayersAndRules(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewTSVWriter(&buf)
+
+	if err := w.WritePlayers([]a2s.Player{{Name: "Player", Score: 7, Duration: 90 * time.Second}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRules(map[string]string{"b": "2", "a": "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "index\tname\tscore\tduration_s\n0\tPlayer\t7\t90\nrule\tvalue\na\t1\nb\t2\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteA3SBRules(t *testing.T) {
+	rules := &a3sb.Rules{
+		RequiredBuild: 123,
+		Difficulty:    &a3sb.Difficulty{Level: 2, ThirdPerson: true},
+		ExtraRules:    map[string]string{"custom": "x"},
+		DLC:           []a3sb.DLCInfo{{Name: "Contact", ID: 1021790, Hash: 0xabcdef}},
+		Mods:          []a3sb.Mod{{Name: "CBA_A3", ID: 450814997, Hash: 0x1}, {ID: 463939057}},
+		Signatures:    []string{"a3"},
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).WriteA3SBRules(rules); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readAll(t, buf.String(), ',')
+	if !slices.Equal(records[0], A3SBColumns) {
+		t.Errorf("header = %v, want %v", records[0], A3SBColumns)
+	}
+
+	want := [][]string{
+		{"rule", "required_build", "", "", "123"},
+		{"rule", "difficulty.third_person", "", "", "true"},
+		{"rule", "extra.custom", "", "", "x"},
+		{"dlc", "Contact", "1021790", "00abcdef", ""},
+		{"mod", "CBA_A3", "450814997", "00000001", ""},
+		{"mod", "463939057", "463939057", "00000000", ""},
+		{"signature", "a3", "", "", ""},
+	}
+	for _, row := range want {
+		if !slices.ContainsFunc(records, func(record []string) bool { return slices.Equal(record, row) }) {
+			t.Errorf("missing row %v", row)
+		}
+	}
+}
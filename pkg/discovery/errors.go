@@ -0,0 +1,14 @@
+package discovery
+
+import "errors"
+
+var (
+	ErrPortRange         = errors.New("discovery: invalid port range")                   // error parse port range
+	ErrNetwork           = errors.New("discovery: invalid network")                      // error parse network
+	ErrNoTargets         = errors.New("discovery: no networks or ports to scan")         // error empty scan
+	ErrNoInterfaces      = errors.New("discovery: no broadcast interfaces or addresses") // error LAN discovery without targets
+	ErrInterface         = errors.New("discovery: fail read interface")                  // error read network interface
+	ErrBroadcast         = errors.New("discovery: fail send broadcast")                  // error send LAN broadcast
+	ErrQueryPortNotFound = errors.New("discovery: query port not found")                 // error no query port candidate answered
+	ErrTooManyTargets    = errors.New("discovery: too many scan targets")                // error scan is too large
+)
@@ -0,0 +1,201 @@
+package discovery
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+const (
+	// DefaultLANWindow is the default time to collect LAN responses.
+	DefaultLANWindow = 2 * time.Second
+
+	// challengeResponse is S2C_CHALLENGE response type of servers requiring a challenge for A2S_INFO.
+	challengeResponse a2s.Flag = 0x41
+)
+
+// DefaultLANPorts are the ports probed by the Source LAN browser and the Arma 3 default query port.
+var DefaultLANPorts = []PortRange{{First: 27015, Last: 27020}, {First: 2303, Last: 2303}}
+
+// LANOptions configures LAN broadcast discovery.
+type LANOptions struct {
+	OnFound    func(Result)  // Called for every responding server as it is found, calls are sequential
+	Interfaces []string      // Interface names to broadcast on, all up broadcast-capable interfaces if empty
+	Addresses  []netip.Addr  // Additional broadcast or unicast addresses to send to
+	Ports      []PortRange   // Ports to broadcast to, DefaultLANPorts if empty
+	Window     time.Duration // Time to collect responses, DefaultLANWindow if zero
+}
+
+// DiscoverLAN broadcasts A2S_INFO on the selected interfaces and ports
+// and gathers replies of all responders within the time window.
+// Servers answering with a challenge are queried again directly. Results are sorted by address.
+func DiscoverLAN(opts LANOptions) ([]Result, error) {
+	targets, err := broadcastAddresses(opts.Interfaces)
+	if err != nil {
+		return nil, err
+	}
+	targets = append(targets, opts.Addresses...)
+	if len(targets) == 0 {
+		return nil, ErrNoInterfaces
+	}
+
+	ports := opts.Ports
+	if len(ports) == 0 {
+		ports = DefaultLANPorts
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = DefaultLANWindow
+	}
+
+	// Unconnected socket to receive replies from any responder, Go enables SO_BROADCAST on UDP sockets
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	request, err := a2s.BuildRequest(a2s.InfoRequest, a2s.NoChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var sendErrs []error
+	sent := 0
+	for _, addr := range targets {
+		for _, r := range ports {
+			for port := uint32(r.First); port <= uint32(r.Last); port++ {
+				target := net.UDPAddrFromAddrPort(netip.AddrPortFrom(addr, uint16(port)))
+				if _, err := conn.WriteToUDP(request, target); err != nil {
+					sendErrs = append(sendErrs, err)
+					continue
+				}
+				sent++
+			}
+		}
+	}
+	if sent == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrBroadcast, errors.Join(sendErrs...))
+	}
+
+	if err := conn.SetReadDeadline(start.Add(window)); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[netip.AddrPort]struct{})
+	var results []Result
+	buf := make([]byte, a2s.DefaultBufferSize)
+
+	for {
+		n, from, err := conn.ReadFromUDPAddrPort(buf)
+		if err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				break
+			}
+			return nil, err
+		}
+		from = netip.AddrPortFrom(from.Addr().Unmap(), from.Port())
+
+		// Only single packet responses are expected for A2S_INFO
+		if n < 5 || binary.LittleEndian.Uint32(buf[:4]) != a2s.NoChallenge {
+			continue
+		}
+		if _, ok := seen[from]; ok {
+			continue
+		}
+
+		response := a2s.Response{Request: a2s.InfoRequest, Type: a2s.Flag(buf[4]), Data: buf[5:n], Ping: time.Since(start)}
+
+		if response.Type == challengeResponse {
+			if n < 9 {
+				continue
+			}
+			retry, err := a2s.BuildRequest(a2s.InfoRequest, binary.BigEndian.Uint32(buf[5:9]))
+			if err == nil {
+				_, _ = conn.WriteToUDPAddrPort(retry, from)
+			}
+			continue
+		}
+
+		info, err := response.Info()
+		if err != nil {
+			continue
+		}
+
+		seen[from] = struct{}{}
+		result := Result{Address: from.String(), Info: info}
+		if opts.OnFound != nil {
+			opts.OnFound(result)
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, _ := netip.ParseAddrPort(results[i].Address)
+		b, _ := netip.ParseAddrPort(results[j].Address)
+		return a.Compare(b) < 0
+	})
+
+	return results, nil
+}
+
+// broadcastAddresses returns IPv4 directed broadcast addresses of the named interfaces
+// or of all up broadcast-capable interfaces if names are empty.
+func broadcastAddresses(names []string) ([]netip.Addr, error) {
+	var ifaces []net.Interface
+
+	if len(names) == 0 {
+		all, err := net.Interfaces()
+		if err != nil {
+			return nil, err
+		}
+		for _, iface := range all {
+			if iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagBroadcast != 0 {
+				ifaces = append(ifaces, iface)
+			}
+		}
+	} else {
+		for _, name := range names {
+			iface, err := net.InterfaceByName(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w %s: %w", ErrInterface, name, err)
+			}
+			ifaces = append(ifaces, *iface)
+		}
+	}
+
+	var addresses []netip.Addr
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrInterface, iface.Name, err)
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip4 := ipNet.IP.To4()
+			if ip4 == nil || len(ipNet.Mask) != net.IPv4len {
+				continue
+			}
+
+			var broadcast [4]byte
+			for i := range broadcast {
+				broadcast[i] = ip4[i] | ^ipNet.Mask[i]
+			}
+			addresses = append(addresses, netip.AddrFrom4(broadcast))
+		}
+	}
+
+	return addresses, nil
+}
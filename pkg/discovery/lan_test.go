@@ -0,0 +1,47 @@
+package discovery
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestDiscoverLAN(t *testing.T) {
+	direct := infoServer(t, "direct", gamePort(2302), false)
+	challenged := infoServer(t, "challenged", gamePort(2402), true)
+
+	ports, err := ParsePorts(fmt.Sprintf("%d,%d", direct, challenged))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Loopback is not broadcast-capable, the unicast address gets the same request
+	results, err := DiscoverLAN(LANOptions{
+		Addresses: []netip.Addr{netip.MustParseAddr("127.0.0.1")},
+		Ports:     ports,
+		Window:    300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("DiscoverLAN() = %+v", results)
+	}
+	for _, result := range results {
+		addr := netip.MustParseAddrPort(result.Address)
+		switch int(addr.Port()) {
+		case direct:
+			if result.Info.Name != "direct" {
+				t.Errorf("direct server info = %+v", result.Info)
+			}
+		case challenged:
+			if result.Info.Name != "challenged" || result.Info.Port != 2402 {
+				t.Errorf("challenged server info = %+v", result.Info)
+			}
+		default:
+			t.Errorf("unexpected responder %s", result.Address)
+		}
+	}
+}
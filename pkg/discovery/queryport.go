@@ -0,0 +1,106 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+// DayZDefaultQueryPort is the default steamQueryPort of DayZ servers.
+const DayZDefaultQueryPort uint16 = 27016
+
+// QueryPort is the query port found for a game port.
+type QueryPort struct {
+	Info      *a2s.Info `json:"info"`      // A2S_INFO response of the query port
+	Address   string    `json:"address"`   // Query address in the format IP:Port
+	Port      uint16    `json:"port"`      // Query port
+	Confirmed bool      `json:"confirmed"` // Game port reported in EDF Info.Port matches
+}
+
+// QueryPortCandidates returns query ports to probe for the game port in order of preference:
+// game port +1 (Arma 3, DayZ and many Unreal/Unity games), DayZ default steamQueryPort 27016
+// and the game port itself (Source).
+func QueryPortCandidates(gamePort uint16) []uint16 {
+	candidates := make([]uint16, 0, 3)
+	add := func(port uint16) {
+		if port == 0 {
+			return
+		}
+		for _, candidate := range candidates {
+			if candidate == port {
+				return
+			}
+		}
+		candidates = append(candidates, port)
+	}
+
+	add(gamePort + 1) // overflows to 0 for port 65535 and is skipped
+	add(DayZDefaultQueryPort)
+	add(gamePort)
+
+	return candidates
+}
+
+// FindQueryPort probes the query port candidates of the game port on host concurrently.
+// A candidate is confirmed if its EDF Info.Port equals the game port, candidates reporting
+// another game port belong to other servers and are skipped. If no candidate is confirmed,
+// the first responding candidate without EDF port is returned with Confirmed set to false.
+func FindQueryPort(host string, gamePort uint16, timeout time.Duration) (*QueryPort, error) {
+	ip, err := net.ResolveIPAddr("ip", host)
+	if err != nil {
+		return nil, err
+	}
+	addr, ok := netip.AddrFromSlice(ip.IP)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNetwork, host)
+	}
+	addr = addr.Unmap()
+
+	if timeout <= 0 {
+		timeout = DefaultScanTimeout
+	}
+
+	candidates := QueryPortCandidates(gamePort)
+	found := make([]*QueryPort, len(candidates))
+	done := make(chan struct{}, len(candidates))
+
+	for i, port := range candidates {
+		go func() {
+			defer func() { done <- struct{}{} }()
+
+			target := netip.AddrPortFrom(addr, port)
+			info, err := probe(target, timeout)
+			if err != nil {
+				return
+			}
+			found[i] = &QueryPort{
+				Info:      info,
+				Address:   target.String(),
+				Port:      port,
+				Confirmed: info.Port == gamePort,
+			}
+		}()
+	}
+	for range candidates {
+		<-done
+	}
+
+	var fallback *QueryPort
+	for _, result := range found {
+		switch {
+		case result == nil:
+		case result.Confirmed:
+			return result, nil
+		case result.Info.Port == 0 && fallback == nil:
+			fallback = result
+		}
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+
+	return nil, fmt.Errorf("%w for game port %d on %s", ErrQueryPortNotFound, gamePort, host)
+}
@@ -0,0 +1,61 @@
+package discovery
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryPortCandidates(t *testing.T) {
+	for port, want := range map[uint16][]uint16{
+		2302:  {2303, 27016, 2302},
+		27015: {27016, 27015},
+		65535: {27016, 65535},
+	} {
+		got := QueryPortCandidates(port)
+		if len(got) != len(want) {
+			t.Errorf("QueryPortCandidates(%d) = %v, want %v", port, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("QueryPortCandidates(%d) = %v, want %v", port, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestFindQueryPort(t *testing.T) {
+	// Game port one below the query port, as Arma 3 and DayZ servers report it
+	confirmed := infoServer(t, "game+1", func(queryPort int) uint16 { return uint16(queryPort - 1) }, false)
+
+	result, err := FindQueryPort("127.0.0.1", uint16(confirmed-1), 300*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Port != uint16(confirmed) || !result.Confirmed || result.Info.Name != "game+1" {
+		t.Errorf("FindQueryPort() = %+v, want confirmed port %d", result, confirmed)
+	}
+
+	// Server without EDF game port is returned unconfirmed
+	unknown := infoServer(t, "no EDF port", gamePort(0), false)
+
+	result, err = FindQueryPort("127.0.0.1", uint16(unknown-1), 300*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Port != uint16(unknown) || result.Confirmed {
+		t.Errorf("FindQueryPort() = %+v, want unconfirmed port %d", result, unknown)
+	}
+}
+
+func TestFindQueryPortMismatch(t *testing.T) {
+	// The server on game port +1 belongs to another game port
+	other := infoServer(t, "other", gamePort(1), false)
+
+	_, err := FindQueryPort("127.0.0.1", uint16(other-1), 300*time.Millisecond)
+	if !errors.Is(err, ErrQueryPortNotFound) {
+		t.Errorf("FindQueryPort() error = %v, want ErrQueryPortNotFound", err)
+	}
+}
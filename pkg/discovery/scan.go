@@ -0,0 +1,244 @@
+// Package discovery finds game servers without a master server:
+// by scanning address ranges and port windows with A2S_INFO.
+package discovery
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+const (
+	// DefaultConcurrency is the default number of simultaneous probes.
+	DefaultConcurrency = 64
+
+	// DefaultScanTimeout is the default time to wait for a probe response.
+	DefaultScanTimeout = time.Second
+
+	// MaxScanTargets limits the number of address and port combinations of a single scan.
+	MaxScanTargets = 1 << 24
+)
+
+// PortRange is an inclusive range of ports.
+type PortRange struct {
+	First uint16 `json:"first"` // First port of the range
+	Last  uint16 `json:"last"`  // Last port of the range, inclusive
+}
+
+// Result is a server that answered A2S_INFO.
+type Result struct {
+	Info    *a2s.Info `json:"info"`    // A2S_INFO response
+	Address string    `json:"address"` // Query address in the format IP:Port
+}
+
+// ScanOptions configures an address range scan.
+type ScanOptions struct {
+	OnFound     func(Result)   // Called for every responding server as it is found, calls are sequential
+	Networks    []netip.Prefix // Networks to scan, single addresses are /32 or /128 prefixes
+	Ports       []PortRange    // Ports to probe on every address
+	Timeout     time.Duration  // Probe response timeout, DefaultScanTimeout if zero
+	Rate        int            // Maximum probes per second, unlimited if zero
+	Concurrency int            // Maximum simultaneous probes, DefaultConcurrency if zero
+}
+
+// ParsePorts parses a comma separated list of ports and port ranges, e.g. "27015-27030,2302-2306,2400".
+func ParsePorts(spec string) ([]PortRange, error) {
+	var ranges []PortRange
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := parsePort(first)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if isRange {
+			if to, err = parsePort(last); err != nil {
+				return nil, err
+			}
+		}
+		if to < from {
+			return nil, fmt.Errorf("%w: %q ends before it starts", ErrPortRange, part)
+		}
+
+		ranges = append(ranges, PortRange{First: from, Last: to})
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("%w: no ports in %q", ErrPortRange, spec)
+	}
+
+	return ranges, nil
+}
+
+// parsePort parses a single non-zero port number.
+func parsePort(value string) (uint16, error) {
+	port, err := strconv.ParseUint(strings.TrimSpace(value), 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("%w: invalid port %q", ErrPortRange, value)
+	}
+
+	return uint16(port), nil
+}
+
+// ParseNetwork parses a CIDR prefix or a single IP address.
+func ParseNetwork(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("%w: %w", ErrNetwork, err)
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Scan sends A2S_INFO to every address of the networks on every port and collects responding servers.
+// Results are sorted by address. Network and broadcast addresses of IPv4 networks larger than /31 are skipped.
+func Scan(opts ScanOptions) ([]Result, error) {
+	if len(opts.Networks) == 0 || len(opts.Ports) == 0 {
+		return nil, ErrNoTargets
+	}
+	if err := checkTargets(opts.Networks, opts.Ports); err != nil {
+		return nil, err
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultScanTimeout
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	targets := make(chan netip.AddrPort, concurrency)
+	found := make(chan Result, concurrency)
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range targets {
+				if info, err := probe(target, timeout); err == nil {
+					found <- Result{Address: target.String(), Info: info}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(targets)
+
+		var tick *time.Ticker
+		if opts.Rate > 0 {
+			tick = time.NewTicker(time.Second / time.Duration(opts.Rate))
+			defer tick.Stop()
+		}
+
+		for _, network := range opts.Networks {
+			for addr := range hosts(network) {
+				for _, ports := range opts.Ports {
+					for port := uint32(ports.First); port <= uint32(ports.Last); port++ {
+						if tick != nil {
+							<-tick.C
+						}
+						targets <- netip.AddrPortFrom(addr, uint16(port))
+					}
+				}
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+
+	var results []Result
+	for result := range found {
+		if opts.OnFound != nil {
+			opts.OnFound(result)
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, _ := netip.ParseAddrPort(results[i].Address)
+		b, _ := netip.ParseAddrPort(results[j].Address)
+		return a.Compare(b) < 0
+	})
+
+	return results, nil
+}
+
+// probe queries A2S_INFO of a single target.
+func probe(target netip.AddrPort, timeout time.Duration) (*a2s.Info, error) {
+	client, err := a2s.NewWithAddr(net.UDPAddrFromAddrPort(target))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	client.Timeout = timeout
+
+	return client.GetInfo()
+}
+
+// checkTargets returns an error if the scan exceeds MaxScanTargets.
+func checkTargets(networks []netip.Prefix, ports []PortRange) error {
+	var portCount int64
+	for _, r := range ports {
+		portCount += int64(r.Last) - int64(r.First) + 1
+	}
+
+	total := new(big.Int)
+	for _, network := range networks {
+		size := new(big.Int).Lsh(big.NewInt(1), uint(network.Addr().BitLen()-network.Bits()))
+		total.Add(total, size)
+	}
+	total.Mul(total, big.NewInt(portCount))
+
+	if total.Cmp(big.NewInt(MaxScanTargets)) > 0 {
+		return fmt.Errorf("%w: %s targets, maximum is %d", ErrTooManyTargets, total, MaxScanTargets)
+	}
+
+	return nil
+}
+
+// hosts iterates over host addresses of the network.
+func hosts(network netip.Prefix) func(yield func(netip.Addr) bool) {
+	return func(yield func(netip.Addr) bool) {
+		first := network.Masked().Addr()
+		skipEdges := first.Is4() && network.Bits() < 31
+
+		for addr := first; addr.IsValid() && network.Contains(addr); addr = addr.Next() {
+			if skipEdges && (addr == first || !network.Contains(addr.Next())) {
+				continue
+			}
+			if !yield(addr) {
+				return
+			}
+		}
+	}
+}
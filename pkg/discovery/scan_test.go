@@ -0,0 +1,129 @@
+package discovery
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// gamePort returns a fixed EDF game port.
+func gamePort(port uint16) func(int) uint16 {
+	return func(int) uint16 { return port }
+}
+
+// infoServer starts a local UDP server answering A2S_INFO, optionally requiring a challenge.
+// The EDF game port is computed from the query port of the server.
+func infoServer(t *testing.T, name string, edfPort func(queryPort int) uint16, challenge bool) int {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	reply := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x49, 17}
+	reply = append(reply, name+"\x00map\x00folder\x00Game\x00"...)
+	reply = binary.LittleEndian.AppendUint16(reply, 440)
+	reply = append(reply, 0, 16, 0, 'd', 'l', 0, 0)
+	reply = append(reply, "1.0\x00"...)
+	reply = append(reply, 0x80)
+	reply = binary.LittleEndian.AppendUint16(reply, edfPort(conn.LocalAddr().(*net.UDPAddr).Port))
+
+	go func() {
+		buf := make([]byte, 1400)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			if n < 5 || buf[4] != 0x54 {
+				continue
+			}
+			if challenge && binary.BigEndian.Uint32(buf[n-4:]) != 0x0A0B0C0D {
+				_, _ = conn.WriteToUDP([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x41, 0x0A, 0x0B, 0x0C, 0x0D}, addr)
+				continue
+			}
+			_, _ = conn.WriteToUDP(reply, addr)
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestParsePorts(t *testing.T) {
+	ranges, err := ParsePorts("27015-27030, 2302-2306,2400")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PortRange{{27015, 27030}, {2302, 2306}, {2400, 2400}}
+	if len(ranges) != len(want) {
+		t.Fatalf("ParsePorts() = %v", ranges)
+	}
+	for i := range want {
+		if ranges[i] != want[i] {
+			t.Errorf("ParsePorts()[%d] = %v, want %v", i, ranges[i], want[i])
+		}
+	}
+
+	for _, spec := range []string{"", "0", "2306-2302", "70000", "a-b"} {
+		if _, err := ParsePorts(spec); err == nil {
+			t.Errorf("ParsePorts(%q) expected error", spec)
+		}
+	}
+}
+
+func TestHosts(t *testing.T) {
+	count := 0
+	for addr := range hosts(netip.MustParsePrefix("10.0.0.0/30")) {
+		if addr.String() != "10.0.0.1" && addr.String() != "10.0.0.2" {
+			t.Errorf("unexpected host %s", addr)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("hosts() yielded %d addresses, want 2", count)
+	}
+
+	if err := checkTargets([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, []PortRange{{1, 2}}); err == nil {
+		t.Error("expected too many targets error")
+	}
+}
+
+func TestScan(t *testing.T) {
+	first := infoServer(t, "first", gamePort(2302), false)
+	second := infoServer(t, "second", gamePort(2402), true)
+
+	network, err := ParseNetwork("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ports, err := ParsePorts(fmt.Sprintf("%d,%d", first, second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := 0
+	results, err := Scan(ScanOptions{
+		Networks:    []netip.Prefix{network},
+		Ports:       ports,
+		Timeout:     500 * time.Millisecond,
+		Rate:        1000,
+		Concurrency: 2,
+		OnFound:     func(Result) { found++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 || found != 2 {
+		t.Fatalf("Scan() = %+v, found %d", results, found)
+	}
+	names := map[string]bool{results[0].Info.Name: true, results[1].Info.Name: true}
+	if !names["first"] || !names["second"] {
+		t.Errorf("Scan() names = %v", names)
+	}
+}
@@ -0,0 +1,9 @@
+package filter
+
+import "errors"
+
+var (
+	ErrSyntax       = errors.New("filter: syntax error")   // error parse expression
+	ErrUnknownField = errors.New("filter: unknown field")  // error field is not known
+	ErrRegexp       = errors.New("filter: invalid regexp") // error compile =~ pattern
+)
@@ -0,0 +1,165 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/keywords"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+// Server is the data a filter is evaluated against, nil or empty parts have missing fields.
+type Server struct {
+	Info     *a2s.Info         // A2S_INFO response
+	Rules    *a3sb.Rules       // A3SB rules, Arma 3 and DayZ only
+	RawRules map[string]string // Standard A2S_RULES
+	Players  []a2s.Player      // A2S_PLAYER response
+}
+
+// staticFields are names of all fields except "rules.*", filled by init.
+var staticFields = make(map[string]bool)
+
+func init() {
+	all := Server{
+		Info:    &a2s.Info{},
+		Rules:   &a3sb.Rules{},
+		Players: []a2s.Player{},
+	}
+	for name := range fields(all, true) {
+		staticFields[name] = true
+	}
+}
+
+// Fields returns the filter fields of the server by name. Values are float64 numbers,
+// strings, bools and []string lists. Durations are seconds, except "ping" in milliseconds.
+//
+// Info fields are named by their JSON tags ("name", "map", "players", "max_players", "version", ...)
+// with the aliases "app_id" and "password", Arma 3 and DayZ keywords are "keywords.arma3.*" and
+// "keywords.dayz.*" by JSON tags of keywords.Arma3 and keywords.DayZ. Players are "players.count"
+// and "players.names". A3SB rules are "mods.count", "mods.ids", "mods.names", "dlc.count",
+// "dlc.names", "creator_dlc.count", "signatures", "signatures.count" and scalar rules
+// "a3sb.*" by JSON tags of a3sb.Rules. Standard and A3SB extra rules are "rules.<name>" strings.
+func Fields(server Server) map[string]any {
+	return fields(server, false)
+}
+
+// fields flattens the server, all keywords are set if allKeywords is true.
+func fields(server Server, allKeywords bool) map[string]any {
+	flat := make(map[string]any, 64)
+
+	if info := server.Info; info != nil {
+		flatten(flat, "", reflect.ValueOf(*info))
+		flat["ping"] = float64(info.Ping.Milliseconds())
+		flat["app_id"] = float64(info.ID)
+		flat["password"] = info.Visibility
+
+		if info.ID == appid.Arma3.Uint64() || allKeywords {
+			flatten(flat, "keywords.arma3.", reflect.ValueOf(*keywords.ParseArma3(info.Keywords)))
+		}
+		if info.ID == appid.DayZ.Uint64() || info.ID == appid.DayZExp.Uint64() || allKeywords {
+			flatten(flat, "keywords.dayz.", reflect.ValueOf(*keywords.ParseDayZ(info.Keywords)))
+		}
+	}
+
+	if server.Players != nil {
+		names := make([]string, 0, len(server.Players))
+		for _, player := range server.Players {
+			names = append(names, player.Name)
+		}
+		flat["players.count"] = float64(len(server.Players))
+		flat["players.names"] = names
+	}
+
+	if rules := server.Rules; rules != nil {
+		flatten(flat, "a3sb.", reflect.ValueOf(*rules))
+
+		ids := make([]string, 0, len(rules.Mods))
+		names := make([]string, 0, len(rules.Mods))
+		for _, mod := range rules.Mods {
+			ids = append(ids, fmt.Sprint(mod.ID))
+			names = append(names, mod.DisplayName())
+		}
+		flat["mods.count"] = float64(len(rules.Mods))
+		flat["mods.ids"] = ids
+		flat["mods.names"] = names
+
+		dlc := make([]string, 0, len(rules.DLC))
+		for _, info := range rules.DLC {
+			dlc = append(dlc, info.Name)
+		}
+		flat["dlc.count"] = float64(len(rules.DLC))
+		flat["dlc.names"] = dlc
+		flat["creator_dlc.count"] = float64(len(rules.CreatorDLC))
+
+		flat["signatures"] = append([]string{}, rules.Signatures...)
+		flat["signatures.count"] = float64(len(rules.Signatures))
+
+		for name, value := range rules.ExtraRules {
+			flat["rules."+name] = value
+		}
+	}
+
+	for name, value := range server.RawRules {
+		flat["rules."+name] = value
+	}
+
+	return flat
+}
+
+// flatten sets scalar and string list fields of the struct by their JSON tags.
+func flatten(flat map[string]any, prefix string, v reflect.Value) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		if value, ok := fieldValue(v.Field(i)); ok {
+			flat[prefix+name] = value
+		}
+	}
+}
+
+// fieldValue converts a struct field to a filter value, false for nested structs and maps.
+func fieldValue(v reflect.Value) (any, bool) {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.Seconds(), true
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.String:
+		return v.String(), true
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil, false
+		}
+		list := make([]string, v.Len())
+		for i := range list {
+			list[i] = v.Index(i).String()
+		}
+		return list, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	if stringer, ok := v.Interface().(fmt.Stringer); ok {
+		return stringer.String(), true
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,465 @@
+// Package filter evaluates server filter expressions against A2S info, players,
+// A3SB rules and parsed Arma 3 and DayZ keywords, e.g.
+//
+//	players >= 10 && map == "chernarusplus" && !keywords.dayz.no3rd && mods.count < 20
+//
+// Operators by precedence: "!" and parentheses, comparisons "==", "!=", "<", "<=", ">", ">=",
+// regexp match "=~" and "!~", membership "in" (list item or substring), then "&&" and "||".
+// Literals are numbers, double quoted strings, true and false. Strings are compared with numbers
+// as numbers. A comparison with a missing field (e.g. DayZ keywords of an Arma 3 server) is false,
+// a missing field alone is false. Field names are listed in Fields.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Filter is a compiled filter expression, safe for concurrent use.
+type Filter struct {
+	root   node
+	source string
+}
+
+// Compile parses the expression, unknown fields are rejected.
+func Compile(expr string) (*Filter, error) {
+	p := &parser{lexer: lexer{input: expr}}
+	p.next()
+
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.unexpected()
+	}
+
+	return &Filter{root: root, source: expr}, nil
+}
+
+// MustCompile is like Compile but panics if the expression can't be parsed.
+func MustCompile(expr string) *Filter {
+	f, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return f
+}
+
+// Match reports whether the server matches the filter.
+func (f *Filter) Match(server Server) bool {
+	return f.MatchFields(Fields(server))
+}
+
+// MatchFields reports whether fields returned by Fields match the filter.
+func (f *Filter) MatchFields(fields map[string]any) bool {
+	return truthy(f.root.eval(fields))
+}
+
+// String returns the source expression.
+func (f *Filter) String() string {
+	return f.source
+}
+
+// node is a node of the expression tree.
+type node interface {
+	eval(fields map[string]any) any
+}
+
+type (
+	literal struct{ value any }
+	field   struct{ name string }
+	not     struct{ x node }
+	logical struct {
+		x, y node
+		and  bool
+	}
+	compare struct {
+		x, y node
+		re   *regexp.Regexp // Compiled pattern of =~ and !~
+		op   string
+	}
+)
+
+func (n literal) eval(map[string]any) any { return n.value }
+
+func (n field) eval(fields map[string]any) any { return fields[n.name] }
+
+func (n not) eval(fields map[string]any) any { return !truthy(n.x.eval(fields)) }
+
+func (n logical) eval(fields map[string]any) any {
+	if truthy(n.x.eval(fields)) != n.and {
+		return !n.and
+	}
+
+	return truthy(n.y.eval(fields))
+}
+
+func (n compare) eval(fields map[string]any) any {
+	x := n.x.eval(fields)
+	if x == nil {
+		return false
+	}
+
+	switch n.op {
+	case "=~", "!~":
+		s, ok := x.(string)
+		return ok && n.re.MatchString(s) == (n.op == "=~")
+	case "in":
+		return contains(n.y.eval(fields), x)
+	}
+
+	y := n.y.eval(fields)
+	if y == nil {
+		return false
+	}
+
+	c, ok := compareValues(x, y)
+	if !ok {
+		return n.op == "!="
+	}
+
+	switch n.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+// compareValues compares values of the same type, strings are compared with numbers as numbers.
+func compareValues(x, y any) (int, bool) {
+	switch x := x.(type) {
+	case float64:
+		if y, ok := number(y); ok {
+			return cmpFloat(x, y), true
+		}
+	case string:
+		switch y := y.(type) {
+		case string:
+			return strings.Compare(x, y), true
+		case float64:
+			if x, ok := number(x); ok {
+				return cmpFloat(x, y), true
+			}
+		}
+	case bool:
+		if y, ok := y.(bool); ok {
+			if x == y {
+				return 0, true
+			}
+			return 1, true
+		}
+	}
+
+	return 0, false
+}
+
+// contains reports whether the list has the value or the string has the substring.
+func contains(container, value any) bool {
+	text := fmt.Sprint(value)
+	if f, ok := value.(float64); ok {
+		text = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	switch container := container.(type) {
+	case []string:
+		return slices.Contains(container, text)
+	case string:
+		return strings.Contains(container, text)
+	}
+
+	return false
+}
+
+// number returns the value as a number, strings are parsed.
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func cmpFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// truthy reports whether the value is true, a non-zero number, a non-empty string or list.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []string:
+		return len(v) > 0
+	}
+
+	return false
+}
+
+// parser is a recursive descent parser of expressions.
+type parser struct {
+	lexer lexer
+	tok   token
+}
+
+func (p *parser) next() {
+	p.tok = p.lexer.next()
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokEOF {
+		return fmt.Errorf("%w: unexpected end of expression", ErrSyntax)
+	}
+	if p.tok.kind == tokInvalid {
+		return fmt.Errorf("%w: %s at position %d", ErrSyntax, p.tok.text, p.tok.pos+1)
+	}
+
+	return fmt.Errorf("%w: unexpected %q at position %d", ErrSyntax, p.tok.text, p.tok.pos+1)
+}
+
+func (p *parser) or() (node, error) {
+	x, err := p.and()
+	for err == nil && p.tok.is(tokOp, "||") {
+		p.next()
+
+		var y node
+		if y, err = p.and(); err == nil {
+			x = logical{x: x, y: y}
+		}
+	}
+
+	return x, err
+}
+
+func (p *parser) and() (node, error) {
+	x, err := p.unary()
+	for err == nil && p.tok.is(tokOp, "&&") {
+		p.next()
+
+		var y node
+		if y, err = p.unary(); err == nil {
+			x = logical{x: x, y: y, and: true}
+		}
+	}
+
+	return x, err
+}
+
+func (p *parser) unary() (node, error) {
+	if p.tok.is(tokOp, "!") {
+		p.next()
+		x, err := p.unary()
+		return not{x: x}, err
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.tok
+	switch {
+	case op.kind == tokOp && slices.Contains([]string{"==", "!=", "<", "<=", ">", ">=", "=~", "!~"}, op.text):
+	case op.kind == tokIdent && op.text == "in":
+	default:
+		return x, nil
+	}
+	p.next()
+
+	y, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	n := compare{x: x, y: y, op: op.text}
+	if op.text == "=~" || op.text == "!~" {
+		lit, _ := y.(literal)
+		pattern, ok := lit.value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs a string pattern at position %d", ErrSyntax, op.text, op.pos+1)
+		}
+		if n.re, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRegexp, err)
+		}
+	}
+
+	return n, nil
+}
+
+func (p *parser) primary() (node, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokNumber:
+		p.next()
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number %q at position %d", ErrSyntax, tok.text, tok.pos+1)
+		}
+		return literal{value: f}, nil
+
+	case tokString:
+		p.next()
+		s, err := strconv.Unquote(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid string %s at position %d", ErrSyntax, tok.text, tok.pos+1)
+		}
+		return literal{value: s}, nil
+
+	case tokIdent:
+		p.next()
+		switch tok.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		}
+		if !staticFields[tok.text] && !strings.HasPrefix(tok.text, "rules.") {
+			return nil, fmt.Errorf("%w: %q at position %d", ErrUnknownField, tok.text, tok.pos+1)
+		}
+		return field{name: tok.text}, nil
+
+	case tokLParen:
+		p.next()
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.unexpected()
+		}
+		p.next()
+		return x, nil
+	}
+
+	return nil, p.unexpected()
+}
+
+// Token kinds.
+const (
+	tokEOF = iota
+	tokInvalid
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+// token is a lexical token with its position in the expression.
+type token struct {
+	text string
+	kind int
+	pos  int
+}
+
+func (t token) is(kind int, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+// lexer splits an expression into tokens.
+type lexer struct {
+	input string
+	pos   int
+}
+
+// operators are operator tokens, longer ones first.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!"}
+
+func (l *lexer) next() token {
+	for l.pos < len(l.input) && strings.IndexByte(" \t\r\n", l.input[l.pos]) >= 0 {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF, pos: l.pos}
+	}
+
+	start, rest := l.pos, l.input[l.pos:]
+	c := rest[0]
+
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokLParen, text: "(", pos: start}
+
+	case c == ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")", pos: start}
+
+	case c == '"':
+		for i := 1; i < len(rest); i++ {
+			switch rest[i] {
+			case '\\':
+				i++
+			case '"':
+				l.pos += i + 1
+				return token{kind: tokString, text: rest[:i+1], pos: start}
+			}
+		}
+		l.pos = len(l.input)
+		return token{kind: tokInvalid, text: "unterminated string", pos: start}
+
+	case c >= '0' && c <= '9' || c == '-' || c == '.' && len(rest) > 1 && rest[1] >= '0' && rest[1] <= '9':
+		end := 1
+		for end < len(rest) && (rest[end] >= '0' && rest[end] <= '9' || rest[end] == '.' || rest[end] == 'e' || rest[end] == 'E') {
+			end++
+		}
+		l.pos += end
+		return token{kind: tokNumber, text: rest[:end], pos: start}
+
+	case isIdent(c) && (c < '0' || c > '9') && c != '.':
+		end := 1
+		for end < len(rest) && isIdent(rest[end]) {
+			end++
+		}
+		l.pos += end
+		return token{kind: tokIdent, text: rest[:end], pos: start}
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			l.pos += len(op)
+			return token{kind: tokOp, text: op, pos: start}
+		}
+	}
+
+	l.pos = len(l.input)
+	return token{kind: tokInvalid, text: fmt.Sprintf("unexpected character %q", c), pos: start}
+}
+
+// isIdent reports whether the character may be a part of a field name.
+func isIdent(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
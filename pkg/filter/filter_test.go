@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+func testServer() Server {
+	return Server{
+		Info: &a2s.Info{
+			Name:       "DayZ EU #1",
+			Map:        "chernarusplus",
+			Version:    "1.27.159674",
+			ID:         appid.DayZ.Uint64(),
+			Players:    42,
+			MaxPlayers: 60,
+			Ping:       35 * time.Millisecond,
+			Keywords:   []string{"battleye", "no3rd", "external", "lqs0", "etm4.000000", "08:47"},
+		},
+		Rules: &a3sb.Rules{
+			Mods:       []a3sb.Mod{{Name: "CF", ID: 1559212036}, {Name: "Community Online Tools", ID: 1564026768}},
+			Signatures: []string{"dayz", "cf"},
+			ExtraRules: map[string]string{"allowedFilePatching": "0"},
+		},
+		Players: []a2s.Player{{Name: "Survivor"}, {Name: "Bandit"}},
+	}
+}
+
+func TestMatch(t *testing.T) {
+	server := testServer()
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`players >= 10 && map == "chernarusplus" && keywords.dayz.no3rd && mods.count < 20`, true},
+		{`players >= 10 && map == "chernarusplus" && !keywords.dayz.no3rd && mods.count < 20`, false},
+		{`players > 42 || max_players == 60`, true},
+		{`!(players < 50) || password`, false},
+		{`name =~ "(?i)^dayz eu" && name !~ "#2"`, true},
+		{`1559212036 in mods.ids && "CF" in mods.names && "Bandit" in players.names`, true},
+		{`"EU" in name && "cf" in signatures`, true},
+		{`ping < 50 && players.count == 2 && app_id == 221100`, true},
+		{`rules.allowedFilePatching == 0 && rules.allowedFilePatching == "0"`, true},
+		{`keywords.dayz.battleye == true && keywords.dayz.lqs == 0 && keywords.dayz.etm == 4`, true},
+		{`keywords.arma3.battleye`, false},
+		{`keywords.arma3.difficulty != 1`, false},
+		{`rules.missing`, false},
+		{`version >= "1.26"`, true},
+	}
+
+	for _, tt := range tests {
+		f, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%s) error: %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(server); got != tt.want {
+			t.Errorf("Match(%s) = %t, want %t", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestMatchInfoOnly(t *testing.T) {
+	server := Server{Info: &a2s.Info{Name: "Source", Players: 3}}
+
+	if !MustCompile(`players == 3 && !mods.count`).Match(server) {
+		t.Error("missing A3SB rules are not false")
+	}
+	if MustCompile(`mods.count < 20`).Match(server) {
+		t.Error("comparison with missing field is true")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		expr string
+	}{
+		{ErrSyntax, ``},
+		{ErrSyntax, `players >=`},
+		{ErrSyntax, `(players > 1`},
+		{ErrSyntax, `players > 1 map`},
+		{ErrSyntax, `name == "open`},
+		{ErrSyntax, `name =~ map`},
+		{ErrSyntax, `players # 1`},
+		{ErrUnknownField, `player > 1`},
+		{ErrUnknownField, `keywords.dayz.unknown`},
+		{ErrRegexp, `name =~ "("`},
+	}
+
+	for _, tt := range tests {
+		if _, err := Compile(tt.expr); !errors.Is(err, tt.err) {
+			t.Errorf("Compile(%s) error = %v, want %v", tt.expr, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Package fleet checks that a group of servers shares the same version, mod set,
+// signatures, required build, DLC and keyword flags.
+//
+// Every server is flattened into a set of named fields (e.g. "version", "mods.1559212036",
+// "signatures.a3", "keywords.battleye"), a field missing on a server has an empty value.
+// Each field is then compared with the value of a reference server or, if no reference is set,
+// with the value most servers have.
+package fleet
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/woozymasta/a2s/internal/flatten"
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+// DefaultConcurrency is the default number of servers queried at once.
+const DefaultConcurrency = 16
+
+// Member is a single queried server of the fleet.
+type Member struct {
+	Err     error       `json:"-"`               // Query error
+	Info    *a2s.Info   `json:"info,omitempty"`  // A2S_INFO response
+	Rules   *a3sb.Rules `json:"rules,omitempty"` // A3SB rules, Arma 3 and DayZ only
+	Address string      `json:"address"`         // Server query address
+}
+
+// Difference is a field whose value on a server differs from the expected one.
+type Difference struct {
+	Field    string `json:"field"`              // Field name, e.g. "version" or "mods.1559212036"
+	Address  string `json:"address"`            // Server query address
+	Value    string `json:"value,omitempty"`    // Value on the server, empty if absent
+	Expected string `json:"expected,omitempty"` // Expected value, empty if expected to be absent
+}
+
+// Report is the result of a fleet check.
+type Report struct {
+	Errors      map[string]string `json:"errors,omitempty"`      // Query errors by server address
+	Reference   string            `json:"reference,omitempty"`   // Reference server address, empty for majority
+	Differences []Difference      `json:"differences,omitempty"` // Fields differing from the expected values
+	Servers     int               `json:"servers"`               // Number of successfully queried servers
+}
+
+// Options configures fleet queries.
+type Options struct {
+	Timeout     time.Duration // Query timeout, a2s default if zero
+	Dialer      a2s.DialFunc  // Custom transport, e.g. SOCKS5 proxy, direct UDP if nil
+	Concurrency int           // Maximum servers queried at once, DefaultConcurrency if zero
+	BufferSize  uint16        // Read buffer size, a2s default if zero
+}
+
+// Query queries A2S_INFO and, for Arma 3 and DayZ, A3SB rules of all servers,
+// at most Options.Concurrency at once.
+// Results are returned in the order of addresses, errors are stored in Member.Err.
+func Query(addresses []string, opts Options) []Member {
+	members := make([]Member, len(addresses))
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		members[i].Address = address
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(member *Member) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			member.Info, member.Rules, member.Err = query(member.Address, opts)
+		}(&members[i])
+	}
+	wg.Wait()
+
+	return members
+}
+
+// query queries a single server.
+func query(address string, opts Options) (*a2s.Info, *a3sb.Rules, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := a2s.NewWithDialer(udpAddr, opts.Dialer)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	if opts.Timeout > 0 {
+		client.Timeout = opts.Timeout
+	}
+	if opts.BufferSize > 0 {
+		client.SetBufferSize(opts.BufferSize)
+	}
+
+	info, err := client.GetInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch info.ID {
+	case appid.Arma3.Uint64(), appid.DayZ.Uint64(), appid.DayZExp.Uint64():
+		rules, err := (&a3sb.Client{Client: client}).GetRules(info.ID)
+		if err != nil {
+			return info, nil, err
+		}
+		return info, rules, nil
+	}
+
+	return info, nil, nil
+}
+
+// Compare compares fields of all successfully queried members. If reference is not empty,
+// it must be the address of one of the members, and its values are expected on all others.
+// Otherwise the value most servers have is expected.
+func Compare(members []Member, reference string) (*Report, error) {
+	report := &Report{Reference: reference}
+
+	fields := make(map[string]struct{})
+	values := make([]map[string]string, 0, len(members))
+	ok := make([]Member, 0, len(members))
+	refIndex := -1
+
+	for _, member := range members {
+		if member.Err != nil {
+			if report.Errors == nil {
+				report.Errors = make(map[string]string)
+			}
+			report.Errors[member.Address] = member.Err.Error()
+			continue
+		}
+
+		flat := flatten.Fields(member.Info, member.Rules)
+		for field := range flat {
+			fields[field] = struct{}{}
+		}
+
+		if member.Address == reference {
+			refIndex = len(ok)
+		}
+		values = append(values, flat)
+		ok = append(ok, member)
+	}
+
+	if reference != "" && refIndex < 0 {
+		return nil, fmt.Errorf("reference server %s is not in the queried fleet", reference)
+	}
+
+	report.Servers = len(ok)
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	for _, field := range names {
+		var expected string
+		if refIndex >= 0 {
+			expected = values[refIndex][field]
+		} else {
+			expected = majority(values, field)
+		}
+
+		for i, member := range ok {
+			if value := values[i][field]; value != expected {
+				report.Differences = append(report.Differences, Difference{
+					Field:    field,
+					Address:  member.Address,
+					Value:    value,
+					Expected: expected,
+				})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// majority returns the most common value of the field, ties are resolved by first occurrence.
+func majority(values []map[string]string, field string) string {
+	counts := make(map[string]int, 2)
+	best, bestCount := "", 0
+
+	for _, flat := range values {
+		value := flat[field]
+		counts[value]++
+		if counts[value] > bestCount {
+			best, bestCount = value, counts[value]
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,114 @@
+package fleet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a2stest"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+func testMember(address, version string, kw []string, mods []a3sb.Mod, signatures []string) Member {
+	return Member{
+		Address: address,
+		Info:    &a2s.Info{Version: version, ID: appid.DayZ.Uint64(), Keywords: kw},
+		Rules:   &a3sb.Rules{Mods: mods, Signatures: signatures, RequiredVersion: 127},
+	}
+}
+
+func testFleet() []Member {
+	mods := []a3sb.Mod{{Name: "CF", ID: 1559212036, Hash: 0xAABBCCDD}}
+	signatures := []string{"dayz", "cf"}
+
+	return []Member{
+		testMember("10.0.0.1:27016", "1.27.159674", []string{"battleye", "no3rd"}, mods, signatures),
+		testMember("10.0.0.2:27016", "1.27.159674", []string{"battleye", "no3rd"}, mods, signatures),
+		testMember("10.0.0.3:27016", "1.26.158593", []string{"battleye"},
+			[]a3sb.Mod{{Name: "CF", ID: 1559212036, Hash: 0x11111111}, {Name: "Extra", ID: 42}},
+			[]string{"dayz"}),
+		{Address: "10.0.0.4:27016", Err: errors.New("i/o timeout")},
+	}
+}
+
+func TestCompareMajority(t *testing.T) {
+	report, err := Compare(testFleet(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Servers != 3 || len(report.Errors) != 1 {
+		t.Errorf("Servers = %d, Errors = %v", report.Servers, report.Errors)
+	}
+
+	got := make(map[string]Difference)
+	for _, diff := range report.Differences {
+		if diff.Address != "10.0.0.3:27016" {
+			t.Errorf("unexpected difference on majority server: %+v", diff)
+		}
+		got[diff.Field] = diff
+	}
+
+	for _, field := range []string{"version", "keywords.no3rd", "mods.1559212036", "mods.42", "signatures.cf"} {
+		if _, ok := got[field]; !ok {
+			t.Errorf("missing difference for %s", field)
+		}
+	}
+	if diff := got["signatures.cf"]; diff.Value != "" || diff.Expected != "present" {
+		t.Errorf("signatures.cf difference = %+v", diff)
+	}
+	if diff := got["version"]; diff.Expected != "1.27.159674" {
+		t.Errorf("version difference = %+v", diff)
+	}
+	if len(got) != 5 {
+		t.Errorf("differences = %+v", report.Differences)
+	}
+}
+
+func TestCompareReference(t *testing.T) {
+	report, err := Compare(testFleet(), "10.0.0.3:27016")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, diff := range report.Differences {
+		if diff.Address == "10.0.0.3:27016" {
+			t.Errorf("reference server reported as different: %+v", diff)
+		}
+	}
+	if len(report.Differences) != 10 {
+		t.Errorf("differences = %+v", report.Differences)
+	}
+
+	if _, err := Compare(testFleet(), "10.0.0.4:27016"); err == nil {
+		t.Error("Compare() accepted failed reference server")
+	}
+}
+
+func TestQueryConcurrency(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	addresses := make([]string, 3)
+	for i := range addresses {
+		server, err := a2stest.NewServer(a2stest.Slow(delay))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = server.Close() }()
+		addresses[i] = server.Addr().String()
+	}
+
+	start := time.Now()
+	members := Query(addresses, Options{Timeout: time.Second, Concurrency: 1})
+	if elapsed := time.Since(start); elapsed < delay*time.Duration(len(addresses)) {
+		t.Errorf("Query with concurrency 1 took %s, servers were queried at once", elapsed)
+	}
+
+	for i, member := range members {
+		if member.Err != nil || member.Info == nil || member.Address != addresses[i] {
+			t.Errorf("member %d: %+v", i, member)
+		}
+	}
+}
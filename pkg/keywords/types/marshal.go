@@ -25,3 +25,87 @@ func (p Platform) MarshalJSON() ([]byte, error) {
 func (ss ServerState) MarshalJSON() ([]byte, error) {
 	return json.Marshal(ss.String())
 }
+
+// UnmarshalJSON for GameType, restores the short value from its name
+func (gt *GameType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	for _, value := range []GameType{
+		GameTApex, GameTCoop, GameTCTF, GameTCTI, GameTDM, GameTEndgame, GameTEscape, GameTKotH, GameTLastman,
+		GameTPatrol, GameTRPG, GameTSandbox, GameTSC, GameTSupport, GameTSurvive, GameTTDM, GameTUnknown,
+		GameTVanguar, GameTWarlord, GameTZeus,
+	} {
+		if value.String() == name {
+			*gt = value
+			return nil
+		}
+	}
+
+	*gt = GameType(name)
+	return nil
+}
+
+// UnmarshalJSON for ServerLang, restores the value from its name
+func (sl *ServerLang) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var raw uint32
+		if errNum := json.Unmarshal(data, &raw); errNum != nil {
+			return err
+		}
+		*sl = ServerLang(raw)
+		return nil
+	}
+
+	for _, value := range []ServerLang{
+		LangEnglish, LangCzech, LangGerman, LangRussian, LangPolish, LangHungarian,
+		LangItalian, LangSpanish, LangFrench, LangChinese, LangJapanese, LangPortuguese,
+	} {
+		if value.String() == name {
+			*sl = value
+			return nil
+		}
+	}
+
+	*sl = LangEnglish
+	return nil
+}
+
+// UnmarshalJSON for Platform, restores the char from its name
+func (p *Platform) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	for _, value := range []Platform{OSWLinux, OSMac, OSOther, OSWindows} {
+		if value.String() == name {
+			*p = value
+			return nil
+		}
+	}
+
+	*p = Platform(name)
+	return nil
+}
+
+// UnmarshalJSON for ServerState, restores the value from its name
+func (ss *ServerState) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	for value := ServerState0; value <= ServerState9; value++ {
+		if value.String() == name {
+			*ss = value
+			return nil
+		}
+	}
+
+	*ss = ServerState0
+	return nil
+}
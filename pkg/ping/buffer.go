@@ -0,0 +1,193 @@
+package ping
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is the default number of latest round-trip times kept for percentiles.
+const DefaultBufferSize = 65535
+
+// Stats holds aggregated ping statistics. Counters, minimum, maximum, average, standard
+// deviation and jitter cover all probes, percentiles cover the latest samples in the buffer.
+type Stats struct {
+	// Sent is the number of transmitted requests.
+	Sent int `json:"sent"`
+
+	// Received is the number of received responses.
+	Received int `json:"received"`
+
+	// Lost is the number of failed requests.
+	Lost int `json:"lost"`
+
+	// Loss is the percentage of failed requests.
+	Loss float64 `json:"loss"`
+
+	// Last is the latest round-trip time observed.
+	Last time.Duration `json:"last"`
+
+	// Min is the smallest round-trip time observed.
+	Min time.Duration `json:"min"`
+
+	// Max is the largest round-trip time observed.
+	Max time.Duration `json:"max"`
+
+	// Avg is the average round-trip time.
+	Avg time.Duration `json:"avg"`
+
+	// StdDev is the standard deviation of round-trip times.
+	StdDev time.Duration `json:"stddev"`
+
+	// Jitter is the mean absolute difference between consecutive round-trip times.
+	Jitter time.Duration `json:"jitter"`
+
+	// P50 is the median round-trip time.
+	P50 time.Duration `json:"p50"`
+
+	// P90 is the 90th percentile of round-trip times.
+	P90 time.Duration `json:"p90"`
+
+	// P99 is the 99th percentile of round-trip times.
+	P99 time.Duration `json:"p99"`
+
+	// Samples is the number of round-trip times the percentiles are calculated from.
+	Samples int `json:"samples"`
+}
+
+// Buffer implements a fixed-size ring buffer for storing ping results
+// together with running totals of all results, safe for concurrent use.
+type Buffer struct {
+	mu    sync.Mutex
+	data  []time.Duration
+	head  int
+	tail  int
+	count int
+
+	sent     int
+	received int
+	min      time.Duration
+	max      time.Duration
+	last     time.Duration
+	mean     float64 // Running mean in nanoseconds
+	m2       float64 // Running sum of squared deviations from the mean
+	jitter   float64 // Sum of absolute consecutive differences in nanoseconds
+}
+
+// NewBuffer creates a ring buffer keeping the latest size results, DefaultBufferSize if size is not positive.
+func NewBuffer(size int) *Buffer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	return &Buffer{
+		data: make([]time.Duration, size),
+	}
+}
+
+// Add inserts a new ping result into the buffer, overwriting the oldest entry if full.
+func (p *Buffer) Add(value time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.data[p.tail] = value
+	p.tail = (p.tail + 1) % len(p.data)
+
+	if p.count < len(p.data) {
+		p.count++
+	} else {
+		p.head = (p.head + 1) % len(p.data)
+	}
+
+	p.sent++
+	p.received++
+
+	if p.received == 1 || value < p.min {
+		p.min = value
+	}
+	if value > p.max {
+		p.max = value
+	}
+	if p.received > 1 {
+		p.jitter += math.Abs(float64(value - p.last))
+	}
+	p.last = value
+
+	// Welford's online variance
+	delta := float64(value) - p.mean
+	p.mean += delta / float64(p.received)
+	p.m2 += delta * (float64(value) - p.mean)
+}
+
+// AddLoss counts a request without response.
+func (p *Buffer) AddLoss() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sent++
+}
+
+// Get returns a slice containing all ping results currently stored in the buffer in insertion order.
+func (p *Buffer) Get() []time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.get()
+}
+
+// get returns buffered results, the caller holds the lock.
+func (p *Buffer) get() []time.Duration {
+	var result []time.Duration
+
+	if p.count == len(p.data) {
+		result = append(result, p.data[p.head:]...)
+	}
+	result = append(result, p.data[:p.tail]...)
+
+	return result
+}
+
+// CalculateStats computes statistics of all results added to the buffer.
+func CalculateStats(buffer *Buffer) Stats {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	stats := Stats{
+		Sent:     buffer.sent,
+		Received: buffer.received,
+		Lost:     buffer.sent - buffer.received,
+		Samples:  buffer.count,
+	}
+	if stats.Sent > 0 {
+		stats.Loss = float64(stats.Lost) * 100 / float64(stats.Sent)
+	}
+	if buffer.received == 0 {
+		return stats
+	}
+
+	stats.Last, stats.Min, stats.Max = buffer.last, buffer.min, buffer.max
+	stats.Avg = time.Duration(math.Round(buffer.mean))
+	stats.StdDev = time.Duration(math.Round(math.Sqrt(buffer.m2 / float64(buffer.received))))
+	if buffer.received > 1 {
+		stats.Jitter = time.Duration(math.Round(buffer.jitter / float64(buffer.received-1)))
+	}
+
+	pings := buffer.get()
+	slices.Sort(pings)
+	stats.P50 = percentile(pings, 50)
+	stats.P90 = percentile(pings, 90)
+	stats.P99 = percentile(pings, 99)
+
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(0, min(rank, len(sorted))-1)]
+}
@@ -0,0 +1,70 @@
+package ping
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateStats(t *testing.T) {
+	buffer := NewBuffer(0)
+	for _, ms := range []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100} {
+		buffer.Add(time.Duration(ms) * time.Millisecond)
+	}
+	buffer.AddLoss()
+	buffer.AddLoss()
+
+	stats := CalculateStats(buffer)
+	want := Stats{
+		Sent:     12,
+		Received: 10,
+		Lost:     2,
+		Last:     100 * time.Millisecond,
+		Min:      10 * time.Millisecond,
+		Max:      100 * time.Millisecond,
+		Avg:      55 * time.Millisecond,
+		Jitter:   10 * time.Millisecond,
+		P50:      50 * time.Millisecond,
+		P90:      90 * time.Millisecond,
+		P99:      100 * time.Millisecond,
+		Samples:  10,
+	}
+
+	// Population standard deviation of 10..100 ms
+	if stddev := stats.StdDev.Round(time.Microsecond); stddev != 28723*time.Microsecond {
+		t.Errorf("StdDev = %s", stddev)
+	}
+	if stats.Loss < 16.66 || stats.Loss > 16.67 {
+		t.Errorf("Loss = %f", stats.Loss)
+	}
+
+	stats.StdDev, stats.Loss = 0, 0
+	if stats != want {
+		t.Errorf("CalculateStats() = %+v\nwant %+v", stats, want)
+	}
+}
+
+func TestBufferTruncation(t *testing.T) {
+	buffer := NewBuffer(3)
+	for i := 1; i <= 5; i++ {
+		buffer.Add(time.Duration(i) * time.Second)
+	}
+
+	if got := buffer.Get(); len(got) != 3 || got[0] != 3*time.Second || got[2] != 5*time.Second {
+		t.Errorf("Get() = %v", got)
+	}
+
+	// Totals cover all samples, percentiles the latest ones
+	stats := CalculateStats(buffer)
+	if stats.Received != 5 || stats.Min != time.Second || stats.Avg != 3*time.Second || stats.Samples != 3 || stats.P50 != 4*time.Second {
+		t.Errorf("CalculateStats() = %+v", stats)
+	}
+}
+
+func TestCalculateStatsEmpty(t *testing.T) {
+	buffer := NewBuffer(0)
+	buffer.AddLoss()
+
+	if stats := CalculateStats(buffer); stats.Loss != 100 || stats.Received != 0 || stats.Avg != 0 {
+		t.Errorf("CalculateStats() = %+v", stats)
+	}
+}
@@ -0,0 +1,79 @@
+// Package ping runs a cyclic A2S_INFO request and accumulates statistics on the response time:
+// loss, minimum, maximum, average, standard deviation, jitter and percentiles of the latest
+// responses kept in a ring buffer.
+package ping
+
+import (
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+// DefaultPeriod is the default interval between requests.
+const DefaultPeriod = time.Second
+
+// Result is the outcome of a single request.
+type Result struct {
+	Err  error     // Request error, the request is counted as lost
+	Info *a2s.Info // A2S_INFO response
+	Seq  int       // Sequence number of the request, starting at 1
+}
+
+// Options configures Run.
+type Options struct {
+	OnResult   func(Result)    // Called after every request, calls are sequential
+	Buffer     *Buffer         // Buffer collecting results, readable while running, a new one if nil
+	Stop       <-chan struct{} // Stops pinging when closed
+	Count      int             // Number of requests, infinite if zero
+	Period     time.Duration   // Interval between request starts, DefaultPeriod if zero
+	Timeout    time.Duration   // Timeout of a single request, the client timeout if zero
+	BufferSize int             // Number of latest responses kept for percentiles of a new buffer, DefaultBufferSize if zero
+}
+
+// Run sends A2S_INFO requests every period until count requests are sent or Stop is closed
+// and returns statistics of all requests.
+func Run(client *a2s.Client, opts Options) Stats {
+	if opts.Period <= 0 {
+		opts.Period = DefaultPeriod
+	}
+	if opts.Timeout > 0 {
+		client.Timeout = opts.Timeout
+	}
+
+	buffer := opts.Buffer
+	if buffer == nil {
+		buffer = NewBuffer(opts.BufferSize)
+	}
+	ticker := time.NewTicker(opts.Period)
+	defer ticker.Stop()
+
+	for seq := 1; opts.Count == 0 || seq <= opts.Count; seq++ {
+		select {
+		case <-opts.Stop:
+			return CalculateStats(buffer)
+		default:
+		}
+
+		info, err := client.GetInfo()
+		if err != nil {
+			buffer.AddLoss()
+		} else {
+			buffer.Add(info.Ping)
+		}
+		if opts.OnResult != nil {
+			opts.OnResult(Result{Seq: seq, Info: info, Err: err})
+		}
+
+		if opts.Count != 0 && seq == opts.Count {
+			break
+		}
+
+		select {
+		case <-opts.Stop:
+			return CalculateStats(buffer)
+		case <-ticker.C:
+		}
+	}
+
+	return CalculateStats(buffer)
+}
@@ -0,0 +1,72 @@
+package ping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a2stest"
+)
+
+func TestRun(t *testing.T) {
+	server, err := a2stest.NewServer(a2stest.Normal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	client, err := a2s.NewWithAddr(server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var seqs []int
+	start := time.Now()
+	stats := Run(client, Options{
+		Count:    4,
+		Period:   20 * time.Millisecond,
+		Timeout:  time.Second,
+		OnResult: func(result Result) { seqs = append(seqs, result.Seq) },
+	})
+
+	if stats.Sent != 4 || stats.Received != 4 || stats.Loss != 0 {
+		t.Errorf("Run() = %+v", stats)
+	}
+	if len(seqs) != 4 || seqs[3] != 4 {
+		t.Errorf("results = %v", seqs)
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("Run() took %s, sub-second period not respected", elapsed)
+	}
+}
+
+func TestRunStop(t *testing.T) {
+	server, err := a2stest.NewServer(a2stest.Silence())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	client, err := a2s.NewWithAddr(server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	stop := make(chan struct{})
+	stats := Run(client, Options{
+		Period:  10 * time.Millisecond,
+		Timeout: 20 * time.Millisecond,
+		Stop:    stop,
+		OnResult: func(result Result) {
+			if result.Seq == 3 {
+				close(stop)
+			}
+		},
+	})
+
+	if stats.Sent != 3 || stats.Lost != 3 || stats.Loss != 100 {
+		t.Errorf("Run() = %+v", stats)
+	}
+}
@@ -0,0 +1,207 @@
+package snapshot
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/woozymasta/a2s/internal/flatten"
+	"github.com/woozymasta/a2s/pkg/keywords"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+// Kind is the kind of change.
+type Kind string
+
+// Kinds of changes.
+const (
+	Added   Kind = "added"   // Value is present only in the new snapshot
+	Removed Kind = "removed" // Value is present only in the old snapshot
+	Changed Kind = "changed" // Value differs between snapshots
+)
+
+// Category is the part of the server state a change belongs to.
+type Category string
+
+// Categories of changes.
+const (
+	CategoryInfo      Category = "info"      // A2S_INFO fields: name, map, version, etc.
+	CategoryKeyword   Category = "keyword"   // Flags and values parsed from A2S_INFO keywords
+	CategoryRule      Category = "rule"      // A2S_RULES values and A3SB rule fields
+	CategoryMod       Category = "mod"       // A3SB mods by Workshop ID
+	CategorySignature Category = "signature" // A3SB accepted signatures
+	CategoryDLC       Category = "dlc"       // A3SB DLC and Creator DLC
+	CategoryPlayer    Category = "player"    // Players by name
+)
+
+// Change is a single difference between two snapshots.
+type Change struct {
+	Category Category `json:"category"`      // Part of the server state
+	Kind     Kind     `json:"kind"`          // Added, removed or changed
+	Field    string   `json:"field"`         // Field name within the category, e.g. "map" or "1559212036"
+	Old      string   `json:"old,omitempty"` // Value in the old snapshot, empty if added
+	New      string   `json:"new,omitempty"` // Value in the new snapshot, empty if removed
+}
+
+// categoryOrder defines the order of categories in Diff results.
+var categoryOrder = []Category{
+	CategoryInfo, CategoryKeyword, CategoryRule, CategoryMod, CategorySignature, CategoryDLC, CategoryPlayer,
+}
+
+// Diff returns changes from one snapshot to another, sorted by category and field.
+// Volatile values such as ping, player count and time left are ignored.
+func Diff(from, to *Snapshot) []Change {
+	before, after := categoryFields(from), categoryFields(to)
+
+	var changes []Change
+	for _, category := range categoryOrder {
+		changes = append(changes, diffFields(category, before[category], after[category])...)
+	}
+
+	return changes
+}
+
+// diffFields compares flattened fields of a single category.
+func diffFields(category Category, before, after map[string]string) []Change {
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		oldValue, inOld := before[name]
+		newValue, inNew := after[name]
+
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Category: category, Kind: Added, Field: name, New: newValue})
+		case !inNew:
+			changes = append(changes, Change{Category: category, Kind: Removed, Field: name, Old: oldValue})
+		case oldValue != newValue:
+			changes = append(changes, Change{Category: category, Kind: Changed, Field: name, Old: oldValue, New: newValue})
+		}
+	}
+
+	return changes
+}
+
+// categoryFields converts a snapshot into comparable fields grouped by category.
+func categoryFields(s *Snapshot) map[Category]map[string]string {
+	fields := make(map[Category]map[string]string, len(categoryOrder))
+	for _, category := range categoryOrder {
+		fields[category] = make(map[string]string)
+	}
+	if s == nil {
+		return fields
+	}
+
+	set := func(category Category, field, value string) {
+		if value != "" {
+			fields[category][field] = value
+		}
+	}
+
+	// flattened fields cover version, keyword flags, required builds, mods, signatures and DLC
+	for field, value := range flatten.Fields(s.Info, s.A3SB) {
+		switch prefix, name, _ := strings.Cut(field, "."); prefix {
+		case "keywords":
+			set(CategoryKeyword, name, value)
+		case "mods":
+			set(CategoryMod, name, value)
+		case "signatures":
+			set(CategorySignature, name, value)
+		case "dlc":
+			set(CategoryDLC, name, value)
+		case "creator_dlc":
+			set(CategoryDLC, "creator "+name, value)
+		case "required_version", "required_build", "allowed_build":
+			set(CategoryRule, field, value)
+		default:
+			set(CategoryInfo, field, value)
+		}
+	}
+
+	if info := s.Info; info != nil {
+		set(CategoryInfo, "name", info.Name)
+		set(CategoryInfo, "map", info.Map)
+		set(CategoryInfo, "folder", info.Folder)
+		set(CategoryInfo, "game", info.Game)
+		set(CategoryInfo, "max_players", strconv.Itoa(int(info.MaxPlayers)))
+		set(CategoryInfo, "port", formatUint(uint64(info.Port)))
+		set(CategoryInfo, "steam_id", formatUint(info.SteamID))
+		set(CategoryInfo, "type", info.ServerType.String())
+		set(CategoryInfo, "environment", info.Environment.String())
+		set(CategoryInfo, "password", strconv.FormatBool(info.Visibility))
+		set(CategoryInfo, "vac", strconv.FormatBool(info.VAC))
+
+		switch info.ID {
+		case appid.Arma3.Uint64():
+			kw := keywords.ParseArma3(info.Keywords)
+			set(CategoryKeyword, "lock", strconv.FormatBool(kw.Lock))
+			set(CategoryKeyword, "dedicated", strconv.FormatBool(kw.Dedicated))
+			set(CategoryKeyword, "gametype", kw.GameType.String())
+			set(CategoryKeyword, "platform", kw.Platform.String())
+			set(CategoryKeyword, "language", kw.Language.String())
+			set(CategoryKeyword, "country", kw.Country)
+
+		case appid.DayZ.Uint64(), appid.DayZExp.Uint64():
+			kw := keywords.ParseDayZ(info.Keywords)
+			set(CategoryKeyword, "shard", kw.Shard)
+			set(CategoryKeyword, "port", formatUint(uint64(kw.GamePort)))
+			set(CategoryKeyword, "etm", strconv.FormatFloat(kw.TimeDayAccel, 'f', -1, 64))
+			set(CategoryKeyword, "entm", strconv.FormatFloat(kw.TimeNightAccel, 'f', -1, 64))
+
+		default:
+			// keywords of other games are free-form tags
+			for _, tag := range info.Keywords {
+				set(CategoryKeyword, tag, "present")
+			}
+		}
+	}
+
+	for key, value := range s.Rules {
+		set(CategoryRule, key, value)
+	}
+
+	if rules := s.A3SB; rules != nil {
+		set(CategoryRule, "description", rules.Description)
+		set(CategoryRule, "island", rules.Island)
+		set(CategoryRule, "platform", rules.Platform)
+		set(CategoryRule, "client_port", formatUint(uint64(rules.ClientPort)))
+		if rules.Language != 0 {
+			set(CategoryRule, "language", rules.Language.String())
+		}
+		if d := rules.Difficulty; d != nil {
+			set(CategoryRule, "difficulty.level", strconv.Itoa(int(d.Level)))
+			set(CategoryRule, "difficulty.level_ai", strconv.Itoa(int(d.AILevel)))
+			set(CategoryRule, "difficulty.advance_flight", strconv.FormatBool(d.AdvanceFlight))
+			set(CategoryRule, "difficulty.third_person", strconv.FormatBool(d.ThirdPerson))
+			set(CategoryRule, "difficulty.crosshair", strconv.FormatBool(d.Crosshair))
+		}
+		for key, value := range rules.ExtraRules {
+			set(CategoryRule, key, value)
+		}
+	}
+
+	for _, player := range s.Players {
+		set(CategoryPlayer, player.Name, "online")
+	}
+
+	return fields
+}
+
+// formatUint formats non-zero values, zero is returned as empty (absent) string.
+func formatUint(value uint64) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(value, 10)
+}
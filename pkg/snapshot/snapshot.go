@@ -0,0 +1,124 @@
+// Package snapshot captures the complete state of a server at a point in time
+// and finds what changed between two captures: map, version, mods, rules or keywords.
+//
+// A snapshot bundles A2S_INFO, the player list, A2S_RULES and, for Arma 3 and DayZ,
+// parsed A3SB rules. Snapshots are saved and loaded as JSON.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+)
+
+// Snapshot is the state of a server at a point in time.
+type Snapshot struct {
+	Time    time.Time         `json:"time"`              // Time of the capture
+	Info    *a2s.Info         `json:"info"`              // A2S_INFO response
+	Rules   map[string]string `json:"rules,omitempty"`   // A2S_RULES response, games without A3SB only
+	A3SB    *a3sb.Rules       `json:"a3sb,omitempty"`    // A3SB rules, Arma 3 and DayZ only
+	Address string            `json:"address"`           // Server query address
+	Players []a2s.Player      `json:"players,omitempty"` // A2S_PLAYER response
+}
+
+// Options configures snapshot queries.
+type Options struct {
+	Timeout    time.Duration // Query timeout, a2s default if zero
+	Dialer     a2s.DialFunc  // Custom transport, e.g. SOCKS5 proxy, direct UDP if nil
+	BufferSize uint16        // Read buffer size, a2s default if zero
+}
+
+// Take queries the server at address in one pipelined round trip and captures its state.
+// A2S_RULES of Arma 3 and DayZ are parsed with A3SB, raw rules are stored for other games.
+func Take(address string, opts Options) (*Snapshot, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	client, err := a2s.NewWithDialer(udpAddr, opts.Dialer)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	if opts.Timeout > 0 {
+		client.Timeout = opts.Timeout
+	}
+	if opts.BufferSize > 0 {
+		client.SetBufferSize(opts.BufferSize)
+	}
+
+	all, err := (&a3sb.Client{Client: client}).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		Time:    time.Now().UTC(),
+		Address: address,
+		Info:    all.Info,
+		Rules:   all.RawRules,
+		A3SB:    all.Rules,
+		Players: all.Players,
+	}
+
+	return snapshot, nil
+}
+
+// Load reads a snapshot from JSON file.
+func Load(path string) (*Snapshot, error) {
+	file, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	snapshot, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// Read decodes a snapshot from JSON.
+func Read(r io.Reader) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Info == nil {
+		return nil, fmt.Errorf("snapshot has no server info")
+	}
+
+	return snapshot, nil
+}
+
+// Save writes the snapshot to JSON file.
+func (s *Snapshot) Save(path string) error {
+	file, err := os.Create(path) // #nosec G304
+	if err != nil {
+		return err
+	}
+
+	if err := s.Write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Write encodes the snapshot as indented JSON.
+func (s *Snapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(s)
+}
@@ -0,0 +1,90 @@
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+	"github.com/woozymasta/a2s/pkg/a3sb"
+	"github.com/woozymasta/a2s/pkg/keywords/types"
+	"github.com/woozymasta/steam/utils/appid"
+)
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Address: "127.0.0.1:2303",
+		Info: &a2s.Info{
+			Name:        "Test server",
+			Map:         "altis",
+			Version:     "2.18.152302",
+			ID:          appid.Arma3.Uint64(),
+			Keywords:    []string{"bt", "r218", "n152302", "lf", "vt"},
+			MaxPlayers:  64,
+			ServerType:  'd',
+			Environment: 'w',
+			Format:      0x49,
+		},
+		Players: []a2s.Player{{Name: "Alice"}, {Name: "Bob"}},
+		A3SB: &a3sb.Rules{
+			Mods:       []a3sb.Mod{{Name: "CBA_A3", ID: 450814997, Hash: 1}, {Name: "ACE", ID: 463939057, Hash: 2}},
+			Signatures: []string{"a3", "cba_v3.18.2"},
+			Language:   types.LangGerman,
+		},
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	saved := testSnapshot()
+	if err := saved.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !loaded.Time.Equal(saved.Time) || loaded.Address != saved.Address {
+		t.Errorf("Load() time/address = %v %s", loaded.Time, loaded.Address)
+	}
+	if loaded.Info.ServerType != 'd' || loaded.Info.Environment != 'w' || loaded.Info.Format != 0x49 {
+		t.Errorf("Load() info enums = %q %q %#x", loaded.Info.ServerType, loaded.Info.Environment, loaded.Info.Format)
+	}
+	if loaded.A3SB.Language != types.LangGerman || len(loaded.A3SB.Mods) != 2 {
+		t.Errorf("Load() a3sb = %+v", loaded.A3SB)
+	}
+	if changes := Diff(saved, loaded); len(changes) != 0 {
+		t.Errorf("Diff() of round trip = %+v", changes)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from, to := testSnapshot(), testSnapshot()
+	to.Info.Map = "stratis"
+	to.Info.Keywords = []string{"bf", "r218", "n152302", "lf", "vt"}
+	to.A3SB.Mods = []a3sb.Mod{{Name: "CBA_A3", ID: 450814997, Hash: 3}, {Name: "RHS", ID: 843425103, Hash: 4}}
+	to.Players = []a2s.Player{{Name: "Bob"}, {Name: "Carol"}}
+
+	want := []Change{
+		{Category: CategoryInfo, Kind: Changed, Field: "map", Old: "altis", New: "stratis"},
+		{Category: CategoryKeyword, Kind: Changed, Field: "battleye", Old: "true", New: "false"},
+		{Category: CategoryMod, Kind: Changed, Field: "450814997", Old: "CBA_A3 (hash 00000001)", New: "CBA_A3 (hash 00000003)"},
+		{Category: CategoryMod, Kind: Removed, Field: "463939057", Old: "ACE (hash 00000002)"},
+		{Category: CategoryMod, Kind: Added, Field: "843425103", New: "RHS (hash 00000004)"},
+		{Category: CategoryPlayer, Kind: Removed, Field: "Alice", Old: "online"},
+		{Category: CategoryPlayer, Kind: Added, Field: "Carol", New: "online"},
+	}
+
+	got := Diff(from, to)
+	if len(got) != len(want) {
+		t.Fatalf("Diff() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Diff()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
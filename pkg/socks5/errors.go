@@ -0,0 +1,11 @@
+package socks5
+
+import "errors"
+
+var (
+	ErrScheme    = errors.New("socks5: unsupported proxy scheme")         // error proxy URL is not socks5://
+	ErrHandshake = errors.New("socks5: handshake failed")                 // error greeting or malformed proxy reply
+	ErrAuth      = errors.New("socks5: authentication failed")            // error no acceptable method or bad credentials
+	ErrAssociate = errors.New("socks5: UDP ASSOCIATE rejected")           // error proxy refused UDP relay
+	ErrAddress   = errors.New("socks5: unsupported or malformed address") // error address type or length in header
+)
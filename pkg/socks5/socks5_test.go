@@ -0,0 +1,314 @@
+package socks5
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+const testChallenge uint32 = 0x11223344
+
+// a2sServer is a local A2S server requiring a challenge and answering rules with a split response.
+func a2sServer(t *testing.T) *net.UDPAddr {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	info := []byte{0xFF, 0xFF, 0xFF, 0xFF, 'I', 17}
+	info = append(info, "Proxied\x00map\x00folder\x00Game\x00"...)
+	info = binary.LittleEndian.AppendUint16(info, 440)
+	info = append(info, 1, 32, 0, 'd', 'l', 0, 0)
+	info = append(info, "1.0\x00"...)
+	info = append(info, 0)
+
+	rules := []byte{0xFF, 0xFF, 0xFF, 0xFF, 'E'}
+	rules = binary.LittleEndian.AppendUint16(rules, 2)
+	rules = append(rules, "mp_timelimit\x0030\x00sv_tags\x00alltalk\x00"...)
+
+	split := func(index int, part []byte) []byte {
+		packet := binary.LittleEndian.AppendUint32(nil, 0xFFFFFFFE)
+		packet = binary.LittleEndian.AppendUint32(packet, 7)
+		packet = append(packet, 2, byte(index))
+		packet = binary.LittleEndian.AppendUint16(packet, 1248)
+		return append(packet, part...)
+	}
+
+	go func() {
+		buf := make([]byte, 1400)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+
+			req := buf[:n]
+			if len(req) < 9 || binary.BigEndian.Uint32(req[n-4:]) != testChallenge {
+				reply := []byte{0xFF, 0xFF, 0xFF, 0xFF, 'A'}
+				_, _ = conn.WriteToUDP(binary.BigEndian.AppendUint32(reply, testChallenge), addr)
+				continue
+			}
+
+			switch a2s.Flag(req[4]) {
+			case a2s.InfoRequest:
+				_, _ = conn.WriteToUDP(info, addr)
+			case a2s.RulesRequest:
+				_, _ = conn.WriteToUDP(split(0, rules[:20]), addr)
+				_, _ = conn.WriteToUDP(split(1, rules[20:]), addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+// socksServer is a minimal in-process SOCKS5 server supporting UDP ASSOCIATE.
+// Username/password authentication is required if user is not empty.
+// Returns the proxy address and the number of relayed client datagrams.
+func socksServer(t *testing.T, user, password string) (string, *atomic.Int32) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	relayed := &atomic.Int32{}
+
+	go func() {
+		for {
+			control, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSOCKS(t, control, user, password, relayed)
+		}
+	}()
+
+	return listener.Addr().String(), relayed
+}
+
+// serveSOCKS negotiates a single association and relays datagrams until the control connection closes.
+func serveSOCKS(t *testing.T, control net.Conn, user, password string, relayed *atomic.Int32) {
+	defer func() { _ = control.Close() }()
+
+	greeting := make([]byte, 2)
+	if _, err := io.ReadFull(control, greeting); err != nil {
+		return
+	}
+	methods := make([]byte, greeting[1])
+	if _, err := io.ReadFull(control, methods); err != nil {
+		return
+	}
+
+	want := byte(methodNoAuth)
+	if user != "" {
+		want = methodUserPass
+	}
+	accepted := false
+	for _, method := range methods {
+		accepted = accepted || method == want
+	}
+	if !accepted {
+		_, _ = control.Write([]byte{version, methodNoAcceptable})
+		return
+	}
+	_, _ = control.Write([]byte{version, want})
+
+	if user != "" {
+		head := make([]byte, 2)
+		if _, err := io.ReadFull(control, head); err != nil {
+			return
+		}
+		name := make([]byte, head[1])
+		_, _ = io.ReadFull(control, name)
+		length := make([]byte, 1)
+		_, _ = io.ReadFull(control, length)
+		pass := make([]byte, length[0])
+		_, _ = io.ReadFull(control, pass)
+
+		if string(name) != user || string(pass) != password {
+			_, _ = control.Write([]byte{userPassVersion, 1})
+			return
+		}
+		_, _ = control.Write([]byte{userPassVersion, 0})
+	}
+
+	request := make([]byte, 3)
+	if _, err := io.ReadFull(control, request); err != nil || request[1] != commandUDPAssociate {
+		return
+	}
+	if _, _, err := readAddress(control); err != nil {
+		return
+	}
+
+	relay, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() { _ = relay.Close() }()
+
+	// Reply with unspecified address to check fallback to the proxy address
+	reply, _ := appendAddress([]byte{version, replySucceeded, 0}, &net.UDPAddr{IP: net.IPv4zero, Port: relay.LocalAddr().(*net.UDPAddr).Port})
+	_, _ = control.Write(reply)
+
+	go func() {
+		var client *net.UDPAddr
+		buf := make([]byte, 2048)
+		for {
+			n, from, err := relay.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+
+			// Datagrams of the client carry the SOCKS header, all others are replies of servers
+			if client == nil || from.String() == client.String() {
+				client = from
+				if n < 10 || buf[2] != 0 || buf[3] != addrIPv4 {
+					continue
+				}
+				relayed.Add(1)
+				target := &net.UDPAddr{IP: net.IP(buf[4:8]), Port: int(binary.BigEndian.Uint16(buf[8:10]))}
+				_, _ = relay.WriteToUDP(buf[10:n], target)
+				continue
+			}
+
+			wrapped, _ := appendAddress([]byte{0, 0, 0}, from)
+			_, _ = relay.WriteToUDP(append(wrapped, buf[:n]...), client)
+		}
+	}()
+
+	_, _ = io.Copy(io.Discard, control)
+}
+
+// dial creates an a2s client to the test server through the proxy.
+func dial(t *testing.T, proxy *Proxy) *a2s.Client {
+	t.Helper()
+
+	client, err := a2s.NewWithDialer(a2sServer(t), proxy.DialUDP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Timeout = 2 * time.Second
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func TestDialUDP(t *testing.T) {
+	address, relayed := socksServer(t, "", "")
+	client := dial(t, &Proxy{Address: address})
+
+	info, err := client.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "Proxied" || info.ID != 440 {
+		t.Errorf("Info = %+v", info)
+	}
+
+	rules, err := client.GetRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules["mp_timelimit"] != "30" || rules["sv_tags"] != "alltalk" {
+		t.Errorf("Rules = %v", rules)
+	}
+
+	responses, err := client.GetPipelined(a2s.InfoRequest, a2s.RulesRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules, err := responses[1].Rules(); err != nil || rules["sv_tags"] != "alltalk" {
+		t.Errorf("pipelined Rules = %v, %v", rules, err)
+	}
+
+	if relayed.Load() < 4 {
+		t.Errorf("proxy relayed %d datagrams, want at least 4", relayed.Load())
+	}
+}
+
+func TestDialUDPAuth(t *testing.T) {
+	address, _ := socksServer(t, "user", "secret")
+
+	client := dial(t, &Proxy{Address: address, Username: "user", Password: "secret"})
+	if _, err := client.GetInfo(); err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := &Proxy{Address: address, Username: "user", Password: "wrong"}
+	if _, err := proxy.DialUDP(a2sServer(t)); !errors.Is(err, ErrAuth) {
+		t.Errorf("wrong password: err = %v, want ErrAuth", err)
+	}
+
+	proxy = &Proxy{Address: address}
+	if _, err := proxy.DialUDP(a2sServer(t)); !errors.Is(err, ErrAuth) {
+		t.Errorf("no credentials: err = %v, want ErrAuth", err)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Proxy
+		err  bool
+	}{
+		{raw: "socks5://127.0.0.1:9050", want: Proxy{Address: "127.0.0.1:9050"}},
+		{raw: "socks5://proxy.example.com", want: Proxy{Address: "proxy.example.com:1080"}},
+		{raw: "socks5://user:p%40ss@[::1]:1081", want: Proxy{Address: "[::1]:1081", Username: "user", Password: "p@ss"}},
+		{raw: "http://127.0.0.1:8080", err: true},
+		{raw: "127.0.0.1:1080", err: true},
+	}
+
+	for _, tt := range tests {
+		proxy, err := ParseURL(tt.raw)
+		if tt.err {
+			if !errors.Is(err, ErrScheme) {
+				t.Errorf("ParseURL(%q) err = %v, want ErrScheme", tt.raw, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseURL(%q): %v", tt.raw, err)
+			continue
+		}
+		if *proxy != tt.want {
+			t.Errorf("ParseURL(%q) = %+v, want %+v", tt.raw, *proxy, tt.want)
+		}
+	}
+}
+
+func TestParseDatagram(t *testing.T) {
+	target := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 27016}
+	datagram, _ := appendAddress([]byte{0, 0, 0}, target)
+	datagram = append(datagram, "payload"...)
+
+	if payload, ok := parseDatagram(datagram, target); !ok || string(payload) != "payload" {
+		t.Errorf("parseDatagram() = %q, %v", payload, ok)
+	}
+
+	fragment := append([]byte{}, datagram...)
+	fragment[2] = 1
+	if _, ok := parseDatagram(fragment, target); ok {
+		t.Error("fragmented datagram accepted")
+	}
+
+	other := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 27017}
+	if _, ok := parseDatagram(datagram, other); ok {
+		t.Error("datagram from other source accepted")
+	}
+	if _, ok := parseDatagram(datagram[:6], target); ok {
+		t.Error("truncated datagram accepted")
+	}
+}
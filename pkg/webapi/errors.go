@@ -0,0 +1,11 @@
+package webapi
+
+import "errors"
+
+var (
+	ErrNoKey   = errors.New("webapi: Steam Web API key is not set")     // error missing API key
+	ErrRequest = errors.New("webapi: request failed")                   // error HTTP request
+	ErrStatus  = errors.New("webapi: unexpected response status")       // error non 200 response
+	ErrDecode  = errors.New("webapi: fail decode server list response") // error decode JSON response
+	ErrAddress = errors.New("webapi: fail parse server address")        // error parse server address
+)
@@ -0,0 +1,151 @@
+// Package webapi is a client for the Steam Web API server list
+// IGameServersService/GetServerList, an HTTPS alternative to the UDP master server.
+//
+// The filter uses the master server syntax, e.g. `\appid\221100\dedicated\1\name_match\*PvE*`,
+// see https://developer.valvesoftware.com/wiki/Master_Server_Query_Protocol#Filter.
+// A Web API key can be obtained at https://steamcommunity.com/dev/apikey
+//
+//	client := webapi.New(key)
+//	servers, err := client.GetServerList(`\appid\221100`)
+//	if err != nil {
+//		panic(err)
+//	}
+//
+//	for _, server := range servers {
+//		addr, err := server.UDPAddr()
+//		if err != nil {
+//			continue
+//		}
+//		a2sClient, err := a2s.NewWithAddr(addr)
+//		...
+//	}
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the Steam Web API endpoint of the server list.
+	DefaultBaseURL = "https://api.steampowered.com/IGameServersService/GetServerList/v1/"
+
+	// DefaultLimit is the default maximum number of servers in a response.
+	DefaultLimit = 10000
+
+	// DefaultTimeout is the default HTTP request timeout.
+	DefaultTimeout = 30 * time.Second
+)
+
+// Client queries the Steam Web API server list.
+type Client struct {
+	HTTPClient *http.Client // HTTP client, a client with DefaultTimeout is used if nil
+	Key        string       // Steam Web API key
+	BaseURL    string       // API endpoint, DefaultBaseURL if empty
+	Limit      int          // Maximum number of servers, DefaultLimit if zero
+}
+
+// Server is a server list entry with the summary fields returned by the API.
+type Server struct {
+	Addr       string `json:"addr"`               // Query address in the format IP:Port
+	Name       string `json:"name"`               // Server name
+	Map        string `json:"map"`                // Current map
+	GameDir    string `json:"gamedir"`            // Game directory, e.g. "dayz" or "cstrike"
+	Product    string `json:"product"`            // Product name
+	Version    string `json:"version"`            // Game version
+	OS         string `json:"os"`                 // Server OS, "l" for Linux, "w" for Windows
+	GameType   string `json:"gametype,omitempty"` // Comma separated server tags, same as A2S_INFO keywords
+	SteamID    uint64 `json:"steamid,string"`     // Server SteamID
+	AppID      uint64 `json:"appid"`              // Steam Application ID of game
+	Region     int    `json:"region"`             // Region code, 255 for world
+	GamePort   uint16 `json:"gameport"`           // Game port for client connections
+	Players    uint16 `json:"players"`            // Number of players
+	MaxPlayers uint16 `json:"max_players"`        // Maximum number of players
+	Bots       uint16 `json:"bots,omitempty"`     // Number of bots
+	Dedicated  bool   `json:"dedicated"`          // Dedicated server
+	Secure     bool   `json:"secure"`             // VAC or BattlEye protected
+}
+
+// New creates a client with the Web API key and default settings.
+func New(key string) *Client {
+	return &Client{Key: key}
+}
+
+// GetServerList returns servers matching the filter in master server syntax.
+func (c *Client) GetServerList(filter string) ([]Server, error) {
+	if c.Key == "" {
+		return nil, ErrNoKey
+	}
+
+	limit := c.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	params := url.Values{}
+	params.Set("key", c.Key)
+	params.Set("format", "json")
+	params.Set("limit", strconv.Itoa(limit))
+	if filter != "" {
+		params.Set("filter", filter)
+	}
+
+	resp, err := httpClient.Get(baseURL + "?" + params.Encode())
+	if err != nil {
+		// url.Error contains the request URL with the key
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrStatus, resp.Status)
+	}
+
+	var result struct {
+		Response struct {
+			Servers []Server `json:"servers"`
+		} `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	return result.Response.Servers, nil
+}
+
+// UDPAddr returns the query address ready for a2s.NewWithAddr.
+func (s Server) UDPAddr() (*net.UDPAddr, error) {
+	addrPort, err := netip.ParseAddrPort(s.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrAddress, s.Addr, err)
+	}
+
+	return net.UDPAddrFromAddrPort(addrPort), nil
+}
+
+// Keywords returns server tags split from GameType.
+func (s Server) Keywords() []string {
+	if s.GameType == "" {
+		return nil
+	}
+
+	return strings.Split(s.GameType, ",")
+}
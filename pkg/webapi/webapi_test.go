@@ -0,0 +1,68 @@
+package webapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testResponse = `{"response":{"servers":[
+	{"addr":"203.0.113.10:27016","gameport":2302,"steamid":"90071992547409920","name":"Test DayZ",
+	 "appid":221100,"gamedir":"dayz","version":"1.27.159674","product":"dayz","region":255,
+	 "players":12,"max_players":60,"bots":0,"map":"chernarusplus","secure":true,"dedicated":true,
+	 "os":"w","gametype":"battleye,no3rd,external,lqs0,etm4.000000"},
+	{"addr":"[2001:db8::1]:27015","appid":730,"name":"IPv6"}
+]}}`
+
+func TestGetServerList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("key") != "secret" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if query.Get("filter") != `\appid\221100` || query.Get("limit") != "100" || query.Get("format") != "json" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(testResponse))
+	}))
+	defer server.Close()
+
+	client := &Client{Key: "secret", BaseURL: server.URL, Limit: 100}
+	servers, err := client.GetServerList(`\appid\221100`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("GetServerList() returned %d servers, want 2", len(servers))
+	}
+
+	dayz := servers[0]
+	if dayz.Name != "Test DayZ" || dayz.SteamID != 90071992547409920 || dayz.GamePort != 2302 || dayz.MaxPlayers != 60 {
+		t.Errorf("server = %+v", dayz)
+	}
+	if keywords := dayz.Keywords(); len(keywords) != 5 || keywords[1] != "no3rd" {
+		t.Errorf("Keywords() = %v", keywords)
+	}
+
+	for _, server := range servers {
+		addr, err := server.UDPAddr()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr.String() != server.Addr {
+			t.Errorf("UDPAddr() = %s, want %s", addr, server.Addr)
+		}
+	}
+
+	client.Key = "wrong"
+	if _, err := client.GetServerList(""); !errors.Is(err, ErrStatus) {
+		t.Errorf("wrong key error = %v", err)
+	}
+
+	client.Key = ""
+	if _, err := client.GetServerList(""); !errors.Is(err, ErrNoKey) {
+		t.Errorf("empty key error = %v", err)
+	}
+}